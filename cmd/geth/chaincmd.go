@@ -17,13 +17,16 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"net"
 	"os"
 	"path"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync/atomic"
@@ -35,13 +38,16 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
 	"gopkg.in/urfave/cli.v1"
 )
@@ -217,6 +223,42 @@ Remove blockchain and state databases`,
 		Description: `
 The arguments are interpreted as block numbers or hashes.
 Use "ethereum dump 0" to dump the genesis block.`,
+	}
+	verifySystemContractsCommand = cli.Command{
+		Action:    utils.MigrateFlags(verifySystemContracts),
+		Name:      "verify-system-contracts",
+		Usage:     "Verify the system contract code against the code bundled for a hardfork",
+		ArgsUsage: "[<blockHash> | <blockNum>]",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.CacheFlag,
+			utils.SyncModeFlag,
+			utils.HardforkFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The verify-system-contracts command compares the code of the BSC system contracts
+in the state of the given block (default = head) with the code bundled with this
+node for the upgrades up to and including the given hardfork, and prints the
+differences. Contracts not upgraded by then keep their genesis code and are only
+listed with their code hash.`,
+	}
+	checkUpgradeCommand = cli.Command{
+		Action:    utils.MigrateFlags(checkUpgrade),
+		Name:      "check-upgrade",
+		Usage:     "Check whether the node is ready for the scheduled hardforks",
+		ArgsUsage: " ",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The check-upgrade command compares the fork schedule stored in the database with
+the one bundled with this binary for the network, reports the next scheduled fork
+along with an estimate of when it activates, and fails if the node would not
+follow the network across it.`,
 	}
 	inspectCommand = cli.Command{
 		Action:    utils.MigrateFlags(inspect),
@@ -696,6 +738,175 @@ func dump(ctx *cli.Context) error {
 	return nil
 }
 
+func verifySystemContracts(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 {
+		utils.Fatalf("This command requires at most one argument.")
+	}
+	if !ctx.IsSet(utils.HardforkFlag.Name) {
+		utils.Fatalf("Missing --%s, want one of %v", utils.HardforkFlag.Name, systemcontracts.Hardforks)
+	}
+	stack := makeFullNode(ctx)
+	defer stack.Close()
+
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+
+	expected, err := systemcontracts.ExpectedCode(ctx.String(utils.HardforkFlag.Name), chain.Genesis().Hash())
+	if err != nil {
+		utils.Fatalf("%v", err)
+	}
+	block := chain.CurrentBlock()
+	if arg := ctx.Args().First(); arg != "" {
+		if hashish(arg) {
+			block = chain.GetBlockByHash(common.HexToHash(arg))
+		} else {
+			num, _ := strconv.Atoi(arg)
+			block = chain.GetBlockByNumber(uint64(num))
+		}
+	}
+	if block == nil {
+		utils.Fatalf("block not found")
+	}
+	statedb, err := chain.StateAt(block.Root())
+	if err != nil {
+		utils.Fatalf("could not create new state: %v", err)
+	}
+	addrs := make([]common.Address, 0, len(systemcontracts.Names))
+	for addr := range systemcontracts.Names {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+
+	fmt.Printf("Verifying system contracts at block %d (%x)\n", block.NumberU64(), block.Hash())
+	mismatches := 0
+	for _, addr := range addrs {
+		code := statedb.GetCode(addr)
+		want, ok := expected[addr]
+		switch {
+		case !ok:
+			fmt.Printf("%-22s %s  genesis    %x\n", systemcontracts.Names[addr], addr.Hex(), crypto.Keccak256(code))
+		case bytes.Equal(code, want):
+			fmt.Printf("%-22s %s  ok         %x\n", systemcontracts.Names[addr], addr.Hex(), crypto.Keccak256(code))
+		default:
+			mismatches++
+			fmt.Printf("%-22s %s  MISMATCH\n", systemcontracts.Names[addr], addr.Hex())
+			fmt.Printf("    on-chain: %d bytes, hash %x\n", len(code), crypto.Keccak256(code))
+			fmt.Printf("    expected: %d bytes, hash %x\n", len(want), crypto.Keccak256(want))
+			fmt.Printf("    first difference at byte %d\n", firstDifference(code, want))
+		}
+	}
+	if mismatches > 0 {
+		return fmt.Errorf("%d system contracts differ from the %s code", mismatches, ctx.String(utils.HardforkFlag.Name))
+	}
+	return nil
+}
+
+// scheduledForks lists the forks of the chain config in activation order, for
+// the readiness report of check-upgrade.
+var scheduledForks = []struct {
+	name  string
+	block func(*params.ChainConfig) *big.Int
+}{
+	{"Homestead", func(c *params.ChainConfig) *big.Int { return c.HomesteadBlock }},
+	{"EIP150", func(c *params.ChainConfig) *big.Int { return c.EIP150Block }},
+	{"EIP155", func(c *params.ChainConfig) *big.Int { return c.EIP155Block }},
+	{"EIP158", func(c *params.ChainConfig) *big.Int { return c.EIP158Block }},
+	{"Byzantium", func(c *params.ChainConfig) *big.Int { return c.ByzantiumBlock }},
+	{"Constantinople", func(c *params.ChainConfig) *big.Int { return c.ConstantinopleBlock }},
+	{"Petersburg", func(c *params.ChainConfig) *big.Int { return c.PetersburgBlock }},
+	{"Istanbul", func(c *params.ChainConfig) *big.Int { return c.IstanbulBlock }},
+	{"MuirGlacier", func(c *params.ChainConfig) *big.Int { return c.MuirGlacierBlock }},
+	{"Ramanujan", func(c *params.ChainConfig) *big.Int { return c.RamanujanBlock }},
+	{"Niels", func(c *params.ChainConfig) *big.Int { return c.NielsBlock }},
+	{"MirrorSync", func(c *params.ChainConfig) *big.Int { return c.MirrorSyncBlock }},
+}
+
+func checkUpgrade(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack)
+	defer db.Close()
+
+	genesisHash := rawdb.ReadCanonicalHash(db, 0)
+	if genesisHash == (common.Hash{}) {
+		utils.Fatalf("Database is not initialized")
+	}
+	stored := rawdb.ReadChainConfig(db, genesisHash)
+	if stored == nil {
+		utils.Fatalf("No chain config stored for genesis %x", genesisHash)
+	}
+	head := rawdb.ReadHeaderNumber(db, rawdb.ReadHeadHeaderHash(db))
+	if head == nil {
+		utils.Fatalf("Head header is missing")
+	}
+	// Nodes of known networks adopt the bundled fork schedule on startup, so
+	// that is what the node will follow if it is compatible with the chain
+	builtin := core.BuiltinChainConfig(genesisHash)
+	config := stored
+	if builtin != nil {
+		if err := stored.CheckCompatible(builtin, *head); err != nil {
+			return fmt.Errorf("bundled fork schedule is incompatible with the chain: %v", err)
+		}
+		config = builtin
+	}
+	blockTime := uint64(0)
+	if config.Parlia != nil {
+		blockTime = config.Parlia.Period
+	}
+	fmt.Printf("Chain %v (genesis %x), head block %d\n", config.ChainID, genesisHash, *head)
+	if builtin == nil {
+		fmt.Println("Unknown network, the fork schedule is taken from the database")
+	}
+	var next string
+	for _, fork := range scheduledForks {
+		block, old := fork.block(config), fork.block(stored)
+		if block == nil && old == nil {
+			continue
+		}
+		var status string
+		switch {
+		case block == nil:
+			status = fmt.Sprintf("removed (was %v)", old)
+		case block.Uint64() <= *head:
+			status = "active"
+		default:
+			remaining := block.Uint64() - *head
+			status = fmt.Sprintf("in %d blocks", remaining)
+			if blockTime > 0 {
+				status += fmt.Sprintf(" (~%v)", time.Duration(remaining*blockTime)*time.Second)
+			}
+			if next == "" {
+				next = fork.name
+			}
+		}
+		if old == nil || block == nil || old.Cmp(block) != 0 {
+			status += ", applied to the database on next start"
+		}
+		fmt.Printf("%-15s %-10v %s\n", fork.name, block, status)
+	}
+	if next == "" {
+		fmt.Println("No upcoming fork is scheduled by this binary, forks announced after its release need an upgrade")
+	} else {
+		fmt.Printf("Next fork known to this binary is %s, forks announced after its release need an upgrade\n", next)
+	}
+	return nil
+}
+
+// firstDifference returns the offset of the first byte differing between a
+// and b, or the length of the shorter one if it is a prefix of the other.
+func firstDifference(a, b []byte) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	if len(a) < len(b) {
+		return len(a)
+	}
+	return len(b)
+}
+
 func inspect(ctx *cli.Context) error {
 	node, _ := makeConfigNode(ctx)
 	defer node.Close()
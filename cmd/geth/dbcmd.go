@@ -0,0 +1,294 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/prometheus/tsdb/fileutil"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	dbCommand = cli.Command{
+		Name:      "db",
+		Usage:     "Low level database operations",
+		ArgsUsage: "",
+		Category:  "BLOCKCHAIN COMMANDS",
+		Subcommands: []cli.Command{
+			dbMigrateAncientCommand,
+			dbRebuildBloombitsCommand,
+		},
+	}
+	dbMigrateAncientCommand = cli.Command{
+		Action:    utils.MigrateFlags(migrateAncient),
+		Name:      "migrate-ancient",
+		Usage:     "Move the ancient chain segments into a new directory",
+		ArgsUsage: "<destination>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+		},
+		Description: `
+The migrate-ancient command moves the freezer holding the ancient chain
+segments into the given directory, e.g. onto a cheaper disk. Every file is
+verified against its original checksum before the old copy is removed.
+The node must be stopped; afterwards it needs to be started with
+--datadir.ancient pointing to the new location.`,
+	}
+	dbRebuildBloombitsCommand = cli.Command{
+		Action:    utils.MigrateFlags(rebuildBloombits),
+		Name:      "rebuild-bloombits",
+		Usage:     "Rebuild and compact the bloombits index used for log filtering",
+		ArgsUsage: " ",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.CacheFlag,
+		},
+		Description: `
+The rebuild-bloombits command drops the bloombits index which speeds up log
+filtering (eth_getLogs), regenerates it from the block headers and compacts
+the database range holding it. Use it when log queries degrade on long running
+nodes. The node must be stopped.`,
+	}
+)
+
+// migrateAncient copies the ancient store into a new directory, verifies the
+// copy and removes the original.
+func migrateAncient(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires the destination directory as argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	// Resolve the current freezer location the same way the node does
+	root := stack.ResolvePath("chaindata")
+	source := ctx.GlobalString(utils.AncientFlag.Name)
+	switch {
+	case source == "":
+		source = filepath.Join(root, "ancient")
+	case !filepath.IsAbs(source):
+		source = stack.ResolvePath(source)
+	}
+	dest, err := filepath.Abs(ctx.Args().First())
+	if err != nil {
+		utils.Fatalf("Invalid destination: %v", err)
+	}
+	if _, err := os.Stat(source); err != nil {
+		utils.Fatalf("No ancient directory found at %s: %v", source, err)
+	}
+	if dest == source {
+		utils.Fatalf("Destination is the current ancient directory")
+	}
+	if entries, err := ioutil.ReadDir(dest); err == nil && len(entries) > 0 {
+		utils.Fatalf("Destination %s is not empty", dest)
+	}
+	// Hold both the key-value store and the freezer locks so that no node can
+	// modify the chain while it is being moved.
+	db, err := rawdb.NewLevelDBDatabase(root, 16, 16, "")
+	if err != nil {
+		utils.Fatalf("Could not open database: %v", err)
+	}
+	defer db.Close()
+
+	lock, _, err := fileutil.Flock(filepath.Join(source, "FLOCK"))
+	if err != nil {
+		utils.Fatalf("Could not lock ancient directory: %v", err)
+	}
+	defer lock.Release()
+
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		utils.Fatalf("Could not create destination: %v", err)
+	}
+	files, err := ioutil.ReadDir(source)
+	if err != nil {
+		utils.Fatalf("Could not list ancient directory: %v", err)
+	}
+	var (
+		start  = time.Now()
+		copied int64
+	)
+	for _, file := range files {
+		if file.IsDir() || file.Name() == "FLOCK" {
+			continue
+		}
+		src, dst := filepath.Join(source, file.Name()), filepath.Join(dest, file.Name())
+		if err := copyVerified(src, dst); err != nil {
+			utils.Fatalf("Failed to migrate %s: %v", file.Name(), err)
+		}
+		copied += file.Size()
+		log.Info("Migrated ancient file", "name", file.Name(), "size", file.Size(), "total", copied, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+	// Everything copied and verified, drop the old files
+	for _, file := range files {
+		if file.IsDir() || file.Name() == "FLOCK" {
+			continue
+		}
+		if err := os.Remove(filepath.Join(source, file.Name())); err != nil {
+			utils.Fatalf("Failed to remove old ancient file %s: %v", file.Name(), err)
+		}
+	}
+	fmt.Printf("Migrated %d bytes of ancient data to %s in %v\n", copied, dest, time.Since(start))
+	fmt.Printf("Start the node with --%s=%s from now on\n", utils.AncientFlag.Name, dest)
+	return nil
+}
+
+// copyVerified copies the file from src into dst, then reads the copy back to
+// ensure its checksum matches the original.
+func copyVerified(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, hasher), in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	want := hasher.Sum(nil)
+
+	check, err := os.Open(dst)
+	if err != nil {
+		return err
+	}
+	defer check.Close()
+
+	hasher.Reset()
+	if _, err := io.Copy(hasher, check); err != nil {
+		return err
+	}
+	if have := hasher.Sum(nil); !bytes.Equal(have, want) {
+		return fmt.Errorf("checksum mismatch: have %x, want %x", have, want)
+	}
+	return nil
+}
+
+// rebuildBloombits drops the bloombits index, regenerates it from the stored
+// headers with the same indexer the node uses and compacts it afterwards.
+func rebuildBloombits(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack)
+	defer db.Close()
+
+	head := rawdb.ReadHeadHeaderHash(db)
+	number := rawdb.ReadHeaderNumber(db, head)
+	if number == nil {
+		utils.Fatalf("Head header is missing")
+	}
+	header := rawdb.ReadHeader(db, head, *number)
+	if header == nil {
+		utils.Fatalf("Head header %x is missing", head)
+	}
+	var sections uint64
+	if *number+1 > params.BloomConfirms {
+		sections = (*number + 1 - params.BloomConfirms) / params.BloomBitsBlocks
+	}
+	// Drop the current index along with the indexer progress
+	start := time.Now()
+	log.Info("Deleting bloombits index", "head", *number)
+	rawdb.DeleteAllBloomBits(db)
+	table := rawdb.NewTable(db, string(rawdb.BloomBitsIndexPrefix))
+	it := table.NewIterator(nil, nil)
+	for it.Next() {
+		if err := table.Delete(it.Key()); err != nil {
+			it.Release()
+			return err
+		}
+	}
+	it.Release()
+	log.Info("Deleted bloombits index", "elapsed", common.PrettyDuration(time.Since(start)))
+
+	// Regenerate the sections up to the head
+	indexer := eth.NewBloomIndexer(db, params.BloomBitsBlocks, params.BloomConfirms)
+	indexer.Start(&staticChain{head: header})
+
+	var (
+		progress = time.NewTicker(8 * time.Second)
+		done     uint64
+		updated  = time.Now()
+	)
+	for done < sections {
+		<-progress.C
+		if stored, _, _ := indexer.Sections(); stored != done {
+			done, updated = stored, time.Now()
+		} else if time.Since(updated) > time.Minute {
+			progress.Stop()
+			indexer.Close()
+			return fmt.Errorf("bloombits indexing stalled at section %d of %d", done, sections)
+		}
+		log.Info("Rebuilding bloombits index", "sections", done, "total", sections, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+	progress.Stop()
+	if err := indexer.Close(); err != nil {
+		return err
+	}
+	log.Info("Compacting bloombits index")
+	compact := time.Now()
+	if err := rawdb.CompactBloomBits(db); err != nil {
+		return err
+	}
+	log.Info("Rebuilt bloombits index", "sections", sections, "compaction", common.PrettyDuration(time.Since(compact)), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// staticChain is a core.ChainIndexerChain over a stopped chain, whose head
+// never changes.
+type staticChain struct {
+	head *types.Header
+	feed event.Feed
+}
+
+func (c *staticChain) CurrentHeader() *types.Header {
+	return c.head
+}
+
+func (c *staticChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return c.feed.Subscribe(ch)
+}
@@ -92,9 +92,16 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		utils.TxPoolAltSlotsFlag,
+		utils.TxPoolAltSendersFlag,
+		utils.TxPoolWithholdFlag,
+		utils.TxPoolWithholdDelayFlag,
 		utils.SyncModeFlag,
 		utils.ExitWhenSyncedFlag,
 		utils.GCModeFlag,
+		utils.TxLookupLimitFlag,
+		utils.TxLookupAccountsFlag,
+		utils.TokenTransferIndexFlag,
 		utils.SnapshotFlag,
 		utils.LightServeFlag,
 		utils.LightLegacyServFlag,
@@ -146,12 +153,16 @@ var (
 		utils.RinkebyFlag,
 		utils.GoerliFlag,
 		utils.VMEnableDebugFlag,
+		utils.VMTraceFlag,
+		utils.VMTraceConfigFlag,
 		utils.NetworkIdFlag,
 		utils.EthStatsURLFlag,
 		utils.FakePoWFlag,
 		utils.NoCompactionFlag,
 		utils.GpoBlocksFlag,
 		utils.GpoPercentileFlag,
+		utils.GpoMinPriceFlag,
+		utils.GpoMaxPriceFlag,
 		utils.EWASMInterpreterFlag,
 		utils.EVMInterpreterFlag,
 		configFileFlag,
@@ -174,10 +185,16 @@ var (
 		utils.WSPortFlag,
 		utils.WSApiFlag,
 		utils.WSAllowedOriginsFlag,
+		utils.WSCompressionFlag,
+		utils.WSMaxMessageSizeFlag,
+		utils.WSMaxNotificationSizeFlag,
+		utils.WSMaxSubscriptionsFlag,
 		utils.IPCDisabledFlag,
 		utils.IPCPathFlag,
 		utils.InsecureUnlockAllowedFlag,
 		utils.RPCGlobalGasCap,
+		utils.RPCArchiveFallbackFlag,
+		utils.RPCRateLimitFlag,
 	}
 
 	whisperFlags = []cli.Flag{
@@ -216,6 +233,8 @@ func init() {
 		removedbCommand,
 		dumpCommand,
 		dumpGenesisCommand,
+		verifySystemContractsCommand,
+		checkUpgradeCommand,
 		inspectCommand,
 		// See accountcmd.go:
 		accountCommand,
@@ -233,6 +252,10 @@ func init() {
 		dumpConfigCommand,
 		// See retesteth.go
 		retestethCommand,
+		// See slashcmd.go:
+		slashEvidenceCommand,
+		// See dbcmd.go:
+		dbCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
@@ -0,0 +1,99 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/consensus/parlia"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	slashEvidenceCommand = cli.Command{
+		Name:     "slash-evidence",
+		Usage:    "Manage double sign evidences detected by the node",
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The node records every header sealed by a validator during verification and
+stores evidence whenever the same validator is seen sealing two different
+headers at the same height.`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "export",
+				Usage:     "Export double sign evidences as JSON",
+				ArgsUsage: "<filename> [<blockNumFirst> <blockNumLast>]",
+				Action:    utils.MigrateFlags(exportSlashEvidence),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+					utils.SyncModeFlag,
+				},
+				Description: `
+Requires a first argument of the file to write to, use "-" for stdout.
+Optional second and third arguments control the first and last block
+to export evidences for. Each evidence contains the RLP encoded pair of
+conflicting headers, as expected by the slash system contract.`,
+			},
+		},
+	}
+)
+
+// exportSlashEvidence writes the double sign evidences recorded in the chain
+// database into a JSON file.
+func exportSlashEvidence(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	first, last := uint64(0), uint64(math.MaxUint64)
+	if len(ctx.Args()) >= 3 {
+		f, ferr := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+		l, lerr := strconv.ParseUint(ctx.Args().Get(2), 10, 64)
+		if ferr != nil || lerr != nil {
+			utils.Fatalf("Export error in parsing parameters: block number not an integer\n")
+		}
+		first, last = f, l
+	}
+	stack := makeFullNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack)
+	defer db.Close()
+
+	evidences, err := parlia.ReadDoubleSignEvidences(db, first, last)
+	if err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+	out, err := json.MarshalIndent(evidences, "", "  ")
+	if err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+	if fp := ctx.Args().First(); fp == "-" {
+		fmt.Println(string(out))
+	} else {
+		if err := ioutil.WriteFile(fp, out, 0644); err != nil {
+			utils.Fatalf("Export error: %v\n", err)
+		}
+		fmt.Printf("Exported %d evidences\n", len(evidences))
+	}
+	return nil
+}
@@ -218,6 +218,10 @@ var (
 		Name:  "nostorage",
 		Usage: "Exclude storage entries (save db lookups)",
 	}
+	HardforkFlag = cli.StringFlag{
+		Name:  "hardfork",
+		Usage: "Hardfork whose bundled system contract code to verify against (ramanujan, niels, mirror)",
+	}
 	IncludeIncompletesFlag = cli.BoolFlag{
 		Name:  "incompletes",
 		Usage: "Include accounts for which we don't have the address (missing preimage)",
@@ -237,6 +241,19 @@ var (
 		Usage: `Blockchain garbage collection mode ("full", "archive")`,
 		Value: "full",
 	}
+	TxLookupLimitFlag = cli.Uint64Flag{
+		Name:  "txlookuplimit",
+		Usage: "Number of recent blocks to maintain transactions index for (default = all blocks, raising it does not reindex dropped blocks)",
+		Value: 0,
+	}
+	TxLookupAccountsFlag = cli.StringFlag{
+		Name:  "txlookupaccounts",
+		Usage: "Comma separated accounts to maintain transactions index for, sent or received (default = all accounts)",
+	}
+	TokenTransferIndexFlag = cli.BoolFlag{
+		Name:  "tokentransferindex",
+		Usage: "Maintain an index of ERC-20/BEP-20 token transfers by account for eth_getTokenTransfers",
+	}
 	SnapshotFlag = cli.BoolFlag{
 		Name:  "snapshot",
 		Usage: `Enables snapshot-database mode -- experimental work in progress feature`,
@@ -394,6 +411,23 @@ var (
 		Usage: "Maximum amount of time non-executable transaction are queued",
 		Value: eth.DefaultConfig.TxPool.Lifetime,
 	}
+	TxPoolAltSlotsFlag = cli.Uint64Flag{
+		Name:  "txpool.altslots",
+		Usage: "Maximum number of transactions in the unpropagated alt lane (0 = disabled)",
+		Value: eth.DefaultConfig.TxPool.AltSlots,
+	}
+	TxPoolAltSendersFlag = cli.StringFlag{
+		Name:  "txpool.altsenders",
+		Usage: "Comma separated accounts allowed to submit transactions to the alt lane",
+	}
+	TxPoolWithholdFlag = cli.StringFlag{
+		Name:  "txpool.withhold",
+		Usage: "Comma separated accounts whose transactions (sent from or to) are withheld from peers",
+	}
+	TxPoolWithholdDelayFlag = cli.DurationFlag{
+		Name:  "txpool.withholddelay",
+		Usage: "Time to withhold matching transactions before propagating them (0 = until inclusion)",
+	}
 	// Performance tuning settings
 	CacheFlag = cli.IntFlag{
 		Name:  "cache",
@@ -520,6 +554,14 @@ var (
 		Name:  "vmdebug",
 		Usage: "Record information useful for VM and contract debugging",
 	}
+	VMTraceFlag = cli.StringFlag{
+		Name:  "vmtrace",
+		Usage: "Name of the registered live tracer to notify of every imported block",
+	}
+	VMTraceConfigFlag = cli.StringFlag{
+		Name:  "vmtrace.config",
+		Usage: "Live tracer configuration (JSON)",
+	}
 	InsecureUnlockAllowedFlag = cli.BoolFlag{
 		Name:  "allow-insecure-unlock",
 		Usage: "Allow insecure account unlocking when account-related RPCs are exposed by http",
@@ -528,6 +570,10 @@ var (
 		Name:  "rpc.gascap",
 		Usage: "Sets a cap on gas that can be used in eth_call/estimateGas",
 	}
+	RPCArchiveFallbackFlag = cli.StringFlag{
+		Name:  "rpc.archivefallback",
+		Usage: "Endpoint of an archive node to forward state queries on pruned blocks to",
+	}
 	// Logging and debug settings
 	EthStatsURLFlag = cli.StringFlag{
 		Name:  "ethstats",
@@ -574,6 +620,10 @@ var (
 		Usage: "Comma separated list of virtual hostnames from which to accept requests (server enforced). Accepts '*' wildcard.",
 		Value: strings.Join(node.DefaultConfig.HTTPVirtualHosts, ","),
 	}
+	RPCRateLimitFlag = cli.StringFlag{
+		Name:  "rpc.ratelimit",
+		Usage: "Comma separated list of per-method call rate limits for the HTTP and WS endpoints (e.g. eth_call=100,eth_getLogs=10)",
+	}
 	RPCApiFlag = cli.StringFlag{
 		Name:  "rpcapi",
 		Usage: "API's offered over the HTTP-RPC interface",
@@ -603,6 +653,22 @@ var (
 		Usage: "Origins from which to accept websockets requests",
 		Value: "",
 	}
+	WSCompressionFlag = cli.BoolFlag{
+		Name:  "ws.compression",
+		Usage: "Enable per-message compression on WS-RPC connections",
+	}
+	WSMaxMessageSizeFlag = cli.Int64Flag{
+		Name:  "ws.maxmessagesize",
+		Usage: "Maximum size in bytes of an incoming WS-RPC message, capped at the default request limit (0 = default request limit)",
+	}
+	WSMaxNotificationSizeFlag = cli.Int64Flag{
+		Name:  "ws.maxnotificationsize",
+		Usage: "Maximum size in bytes of an outgoing WS-RPC subscription notification (0 = unlimited)",
+	}
+	WSMaxSubscriptionsFlag = cli.IntFlag{
+		Name:  "ws.maxsubscriptions",
+		Usage: "Maximum number of active subscriptions per WS-RPC connection (0 = unlimited)",
+	}
 	GraphQLEnabledFlag = cli.BoolFlag{
 		Name:  "graphql",
 		Usage: "Enable the GraphQL server",
@@ -715,6 +781,16 @@ var (
 		Usage: "Suggested gas price is the given percentile of a set of recent transaction gas prices",
 		Value: eth.DefaultConfig.GPO.Percentile,
 	}
+	GpoMinPriceFlag = BigFlag{
+		Name:  "gpominprice",
+		Usage: "Floor of the suggested gas price (wei)",
+		Value: new(big.Int),
+	}
+	GpoMaxPriceFlag = BigFlag{
+		Name:  "gpomaxprice",
+		Usage: "Ceiling of the suggested gas price (wei)",
+		Value: eth.DefaultConfig.GPO.MaxPrice,
+	}
 	WhisperEnabledFlag = cli.BoolFlag{
 		Name:  "shh",
 		Usage: "Enable Whisper",
@@ -988,6 +1064,20 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(RPCVirtualHostsFlag.Name) {
 		cfg.HTTPVirtualHosts = splitAndTrim(ctx.GlobalString(RPCVirtualHostsFlag.Name))
 	}
+	if ctx.GlobalIsSet(RPCRateLimitFlag.Name) {
+		cfg.RPCRateLimits = make(map[string]float64)
+		for _, entry := range splitAndTrim(ctx.GlobalString(RPCRateLimitFlag.Name)) {
+			parts := strings.Split(entry, "=")
+			if len(parts) != 2 {
+				Fatalf("Invalid rate limit %q, expected method=limit", entry)
+			}
+			limit, err := strconv.ParseFloat(parts[1], 64)
+			if err != nil {
+				Fatalf("Invalid rate limit %q: %v", entry, err)
+			}
+			cfg.RPCRateLimits[parts[0]] = limit
+		}
+	}
 }
 
 // setGraphQL creates the GraphQL listener interface string from the set
@@ -1023,6 +1113,18 @@ func setWS(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(WSAllowedOriginsFlag.Name) {
 		cfg.WSOrigins = splitAndTrim(ctx.GlobalString(WSAllowedOriginsFlag.Name))
 	}
+	if ctx.GlobalIsSet(WSCompressionFlag.Name) {
+		cfg.WSCompression = ctx.GlobalBool(WSCompressionFlag.Name)
+	}
+	if ctx.GlobalIsSet(WSMaxMessageSizeFlag.Name) {
+		cfg.WSMaxMessageSize = ctx.GlobalInt64(WSMaxMessageSizeFlag.Name)
+	}
+	if ctx.GlobalIsSet(WSMaxNotificationSizeFlag.Name) {
+		cfg.WSMaxNotificationSize = ctx.GlobalInt64(WSMaxNotificationSizeFlag.Name)
+	}
+	if ctx.GlobalIsSet(WSMaxSubscriptionsFlag.Name) {
+		cfg.WSMaxSubscriptions = ctx.GlobalInt(WSMaxSubscriptionsFlag.Name)
+	}
 	if ctx.GlobalIsSet(WSApiFlag.Name) {
 		cfg.WSModules = splitAndTrim(ctx.GlobalString(WSApiFlag.Name))
 	}
@@ -1317,6 +1419,12 @@ func setGPO(ctx *cli.Context, cfg *gasprice.Config) {
 	if ctx.GlobalIsSet(GpoPercentileFlag.Name) {
 		cfg.Percentile = ctx.GlobalInt(GpoPercentileFlag.Name)
 	}
+	if ctx.GlobalIsSet(GpoMinPriceFlag.Name) {
+		cfg.MinPrice = GlobalBig(ctx, GpoMinPriceFlag.Name)
+	}
+	if ctx.GlobalIsSet(GpoMaxPriceFlag.Name) {
+		cfg.MaxPrice = GlobalBig(ctx, GpoMaxPriceFlag.Name)
+	}
 }
 
 func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
@@ -1360,6 +1468,19 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolAltSlotsFlag.Name) {
+		cfg.AltSlots = ctx.GlobalUint64(TxPoolAltSlotsFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolAltSendersFlag.Name) {
+		senders := strings.Split(ctx.GlobalString(TxPoolAltSendersFlag.Name), ",")
+		for _, account := range senders {
+			if trimmed := strings.TrimSpace(account); !common.IsHexAddress(trimmed) {
+				Fatalf("Invalid account in --txpool.altsenders: %s", trimmed)
+			} else {
+				cfg.AltSenders = append(cfg.AltSenders, common.HexToAddress(trimmed))
+			}
+		}
+	}
 }
 
 func setEthash(ctx *cli.Context, cfg *eth.Config) {
@@ -1550,6 +1671,35 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(CacheNoPrefetchFlag.Name) {
 		cfg.NoPrefetch = ctx.GlobalBool(CacheNoPrefetchFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxLookupLimitFlag.Name) {
+		cfg.TxLookupLimit = ctx.GlobalUint64(TxLookupLimitFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxLookupAccountsFlag.Name) {
+		accounts := strings.Split(ctx.GlobalString(TxLookupAccountsFlag.Name), ",")
+		for _, account := range accounts {
+			if trimmed := strings.TrimSpace(account); !common.IsHexAddress(trimmed) {
+				Fatalf("Invalid account in --txlookupaccounts: %s", trimmed)
+			} else {
+				cfg.TxLookupAccounts = append(cfg.TxLookupAccounts, common.HexToAddress(trimmed))
+			}
+		}
+	}
+	if ctx.GlobalIsSet(TokenTransferIndexFlag.Name) {
+		cfg.TokenTransferIndex = ctx.GlobalBool(TokenTransferIndexFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolWithholdFlag.Name) {
+		accounts := strings.Split(ctx.GlobalString(TxPoolWithholdFlag.Name), ",")
+		for _, account := range accounts {
+			if trimmed := strings.TrimSpace(account); !common.IsHexAddress(trimmed) {
+				Fatalf("Invalid account in --txpool.withhold: %s", trimmed)
+			} else {
+				cfg.WithheldTxAddresses = append(cfg.WithheldTxAddresses, common.HexToAddress(trimmed))
+			}
+		}
+	}
+	if ctx.GlobalIsSet(TxPoolWithholdDelayFlag.Name) {
+		cfg.WithheldTxDelay = ctx.GlobalDuration(TxPoolWithholdDelayFlag.Name)
+	}
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheTrieFlag.Name) {
 		cfg.TrieCleanCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheTrieFlag.Name) / 100
 	}
@@ -1574,12 +1724,21 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 		cfg.EWASMInterpreter = ctx.GlobalString(EWASMInterpreterFlag.Name)
 	}
 
+	if ctx.GlobalIsSet(VMTraceFlag.Name) {
+		cfg.LiveTracer = ctx.GlobalString(VMTraceFlag.Name)
+	}
+	if ctx.GlobalIsSet(VMTraceConfigFlag.Name) {
+		cfg.LiveTracerConfig = ctx.GlobalString(VMTraceConfigFlag.Name)
+	}
 	if ctx.GlobalIsSet(EVMInterpreterFlag.Name) {
 		cfg.EVMInterpreter = ctx.GlobalString(EVMInterpreterFlag.Name)
 	}
 	if ctx.GlobalIsSet(RPCGlobalGasCap.Name) {
 		cfg.RPCGasCap = new(big.Int).SetUint64(ctx.GlobalUint64(RPCGlobalGasCap.Name))
 	}
+	if ctx.GlobalIsSet(RPCArchiveFallbackFlag.Name) {
+		cfg.ArchiveFallback = ctx.GlobalString(RPCArchiveFallbackFlag.Name)
+	}
 	if ctx.GlobalIsSet(DNSDiscoveryFlag.Name) {
 		urls := ctx.GlobalString(DNSDiscoveryFlag.Name)
 		if urls == "" {
@@ -138,4 +138,6 @@ type PoSA interface {
 	IsSystemTransaction(tx *types.Transaction, header *types.Header) (bool, error)
 	IsSystemContract(to *common.Address) bool
 	EnoughDistance(chain ChainReader, header *types.Header) bool
+	GetSafeHeader(chain ChainReader, header *types.Header) *types.Header
+	GetFinalizedHeader(chain ChainReader, header *types.Header) *types.Header
 }
@@ -17,9 +17,12 @@
 package parlia
 
 import (
+	"fmt"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -29,15 +32,27 @@ type API struct {
 	parlia *Parlia
 }
 
+// header resolves the given block number or tag to a header of the local chain,
+// the current head if none is given.
+func (api *API) header(number *rpc.BlockNumber) *types.Header {
+	switch {
+	case number == nil || *number == rpc.LatestBlockNumber:
+		return api.chain.CurrentHeader()
+	case *number == rpc.SafeBlockNumber:
+		return api.parlia.GetSafeHeader(api.chain, api.chain.CurrentHeader())
+	case *number == rpc.FinalizedBlockNumber:
+		return api.parlia.GetFinalizedHeader(api.chain, api.chain.CurrentHeader())
+	case *number < 0:
+		return nil
+	default:
+		return api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+}
+
 // GetSnapshot retrieves the state snapshot at a given block.
 func (api *API) GetSnapshot(number *rpc.BlockNumber) (*Snapshot, error) {
 	// Retrieve the requested block number (or current if none requested)
-	var header *types.Header
-	if number == nil || *number == rpc.LatestBlockNumber {
-		header = api.chain.CurrentHeader()
-	} else {
-		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
-	}
+	header := api.header(number)
 	// Ensure we have an actually valid block and return its snapshot
 	if header == nil {
 		return nil, errUnknownBlock
@@ -57,12 +72,7 @@ func (api *API) GetSnapshotAtHash(hash common.Hash) (*Snapshot, error) {
 // GetValidators retrieves the list of validators at the specified block.
 func (api *API) GetValidators(number *rpc.BlockNumber) ([]common.Address, error) {
 	// Retrieve the requested block number (or current if none requested)
-	var header *types.Header
-	if number == nil || *number == rpc.LatestBlockNumber {
-		header = api.chain.CurrentHeader()
-	} else {
-		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
-	}
+	header := api.header(number)
 	// Ensure we have an actually valid block and return the validators from its snapshot
 	if header == nil {
 		return nil, errUnknownBlock
@@ -86,3 +96,87 @@ func (api *API) GetValidatorsAtHash(hash common.Hash) ([]common.Address, error)
 	}
 	return snap.validators(), nil
 }
+
+// ValidatorInfo describes a single member of the validator set. The voting
+// power and jailed flag are only available if the state of the block is.
+type ValidatorInfo struct {
+	Address     common.Address `json:"address"`
+	VotingPower *uint64        `json:"votingPower,omitempty"`
+	Jailed      bool           `json:"jailed"`
+}
+
+// ValidatorSet is the validator set active at a given block.
+type ValidatorSet struct {
+	Number     uint64          `json:"number"`
+	Hash       common.Hash     `json:"hash"`
+	Validators []ValidatorInfo `json:"validators"`
+}
+
+// GetValidatorSetAtBlock retrieves the validator set active at the specified
+// block together with the voting power of each validator.
+func (api *API) GetValidatorSetAtBlock(number *rpc.BlockNumber) (*ValidatorSet, error) {
+	// Retrieve the requested block number (or current if none requested)
+	header := api.header(number)
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return api.validatorSet(header)
+}
+
+// GetValidatorSetAtHash retrieves the validator set active at the specified
+// block together with the voting power of each validator.
+func (api *API) GetValidatorSetAtHash(hash common.Hash) (*ValidatorSet, error) {
+	header := api.chain.GetHeaderByHash(hash)
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return api.validatorSet(header)
+}
+
+// validatorSet assembles the validator set of a block from its consensus
+// snapshot, enriching it with voting powers if the block state is available.
+func (api *API) validatorSet(header *types.Header) (*ValidatorSet, error) {
+	snap, err := api.parlia.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	// Pruned nodes can't serve the contract state of old blocks, fall back
+	// to the bare snapshot in that case.
+	infos, err := api.parlia.getValidatorInfos(header.Hash())
+	if err != nil {
+		log.Debug("Validator voting powers unavailable", "number", header.Number, "hash", header.Hash(), "err", err)
+	}
+	set := &ValidatorSet{
+		Number: snap.Number,
+		Hash:   snap.Hash,
+	}
+	for _, val := range snap.validators() {
+		if info, ok := infos[val]; ok {
+			set.Validators = append(set.Validators, info)
+		} else {
+			set.Validators = append(set.Validators, ValidatorInfo{Address: val})
+		}
+	}
+	return set, nil
+}
+
+// GetDoubleSignEvidences retrieves the double sign evidences detected by this
+// node between the given blocks (inclusive).
+func (api *API) GetDoubleSignEvidences(from rpc.BlockNumber, to *rpc.BlockNumber) ([]*DoubleSignEvidence, error) {
+	// Evidences are only recorded for imported blocks, pending is the head
+	latest := rpc.LatestBlockNumber
+	if from == rpc.PendingBlockNumber {
+		from = latest
+	}
+	if to != nil && *to == rpc.PendingBlockNumber {
+		to = &latest
+	}
+	start, end := api.header(&from), api.header(to)
+	if start == nil || end == nil {
+		return nil, errUnknownBlock
+	}
+	if start.Number.Cmp(end.Number) > 0 {
+		return nil, fmt.Errorf("invalid block range %d-%d", start.Number, end.Number)
+	}
+	return ReadDoubleSignEvidences(api.parlia.db, start.Number.Uint64(), end.Number.Uint64())
+}
@@ -0,0 +1,99 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package parlia
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// systemEventABIs are the bundled ABIs used to decode system contract events.
+var systemEventABIs = map[common.Address]abi.ABI{
+	common.HexToAddress(systemcontracts.ValidatorContract):    mustParseABI(validatorSetABI),
+	common.HexToAddress(systemcontracts.SlashContract):        mustParseABI(slashABI),
+	common.HexToAddress(systemcontracts.TokenHubContract):     mustParseABI(tokenHubABI),
+	common.HexToAddress(systemcontracts.TokenManagerContract): mustParseABI(tokenManagerABI),
+}
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// SystemEvent is a log emitted by one of the system contracts. The event name
+// and arguments are only filled in if the contract ABI is bundled with the node.
+type SystemEvent struct {
+	Contract string                 `json:"contract"`
+	Event    string                 `json:"event,omitempty"`
+	Args     map[string]interface{} `json:"args,omitempty"`
+	Log      *types.Log             `json:"log"`
+}
+
+// DecodeSystemEvent labels a system contract log and decodes its arguments
+// if the event is known. Logs of other contracts yield nil.
+func DecodeSystemEvent(log *types.Log) *SystemEvent {
+	name, ok := systemcontracts.Names[log.Address]
+	if !ok {
+		return nil
+	}
+	event := &SystemEvent{Contract: name, Log: log}
+
+	contractABI, ok := systemEventABIs[log.Address]
+	if !ok || len(log.Topics) == 0 {
+		return event
+	}
+	ev, err := contractABI.EventByID(log.Topics[0])
+	if err != nil {
+		return event
+	}
+	args := make(map[string]interface{})
+	if len(log.Data) > 0 {
+		if err := contractABI.UnpackIntoMap(args, ev.Name, log.Data); err != nil {
+			return event
+		}
+	}
+	// Indexed arguments live in the topics, the bundled ABIs only use static
+	// types for those so they can be decoded directly.
+	topics := log.Topics[1:]
+	for _, input := range ev.Inputs {
+		if !input.Indexed {
+			continue
+		}
+		if len(topics) == 0 {
+			return event
+		}
+		switch input.Type.T {
+		case abi.AddressTy:
+			args[input.Name] = common.BytesToAddress(topics[0][:])
+		case abi.UintTy:
+			args[input.Name] = new(big.Int).SetBytes(topics[0][:])
+		default:
+			args[input.Name] = topics[0]
+		}
+		topics = topics[1:]
+	}
+	event.Event, event.Args = ev.Name, args
+	return event
+}
@@ -0,0 +1,73 @@
+package parlia
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestDecodeSystemEvent(t *testing.T) {
+	validatorSet := common.HexToAddress(systemcontracts.ValidatorContract)
+	deposit := systemEventABIs[validatorSet].Events["validatorDeposit"]
+
+	validator := randomAddress()
+	log := &types.Log{
+		Address: validatorSet,
+		Topics: []common.Hash{
+			deposit.ID(),
+			common.BytesToHash(validator[:]),
+			common.BigToHash(big.NewInt(1000)),
+		},
+	}
+	event := DecodeSystemEvent(log)
+	if assert.NotNil(t, event) {
+		assert.Equal(t, "ValidatorSet", event.Contract)
+		assert.Equal(t, "validatorDeposit", event.Event)
+		assert.Equal(t, validator, event.Args["validator"])
+		assert.Equal(t, big.NewInt(1000), event.Args["amount"])
+	}
+	// Token hub and token manager events are decoded as well
+	tokenHub := common.HexToAddress(systemcontracts.TokenHubContract)
+	transferOut := systemEventABIs[tokenHub].Events["transferOutSuccess"]
+	token, sender := randomAddress(), randomAddress()
+	data, err := transferOut.Inputs.Pack(token, sender, big.NewInt(5), big.NewInt(1))
+	if !assert.NoError(t, err) {
+		return
+	}
+	event = DecodeSystemEvent(&types.Log{Address: tokenHub, Topics: []common.Hash{transferOut.ID()}, Data: data})
+	if assert.NotNil(t, event) {
+		assert.Equal(t, "TokenHub", event.Contract)
+		assert.Equal(t, "transferOutSuccess", event.Event)
+		assert.Equal(t, token, event.Args["bep20Addr"])
+		assert.Equal(t, sender, event.Args["senderAddr"])
+		assert.Equal(t, big.NewInt(5), event.Args["amount"])
+	}
+	tokenManager := common.HexToAddress(systemcontracts.TokenManagerContract)
+	bind := systemEventABIs[tokenManager].Events["bindSuccess"]
+	data, err = bind.Inputs.NonIndexed().Pack("ABC-123", big.NewInt(1000), big.NewInt(100))
+	if !assert.NoError(t, err) {
+		return
+	}
+	event = DecodeSystemEvent(&types.Log{Address: tokenManager, Topics: []common.Hash{bind.ID(), common.BytesToHash(token[:])}, Data: data})
+	if assert.NotNil(t, event) {
+		assert.Equal(t, "TokenManager", event.Contract)
+		assert.Equal(t, "bindSuccess", event.Event)
+		assert.Equal(t, token, event.Args["contractAddr"])
+		assert.Equal(t, "ABC-123", event.Args["bep2Symbol"])
+		assert.Equal(t, big.NewInt(1000), event.Args["totalSupply"])
+	}
+	// Events of system contracts without a bundled ABI are only labelled
+	log = &types.Log{Address: common.HexToAddress(systemcontracts.GovHubContract), Topics: []common.Hash{{0x01}}}
+	event = DecodeSystemEvent(log)
+	if assert.NotNil(t, event) {
+		assert.Equal(t, "GovHub", event.Contract)
+		assert.Empty(t, event.Event)
+	}
+	// Other contracts are not system events at all
+	assert.Nil(t, DecodeSystemEvent(&types.Log{Address: randomAddress()}))
+}
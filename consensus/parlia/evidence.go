@@ -0,0 +1,129 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package parlia
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const inMemorySeenHeaders = 4096 // Number of recent (validator, height) seals to keep in memory
+
+// evidencePrefix + num (uint64 big endian) + validator -> double sign evidence
+var evidencePrefix = []byte("parlia-evidence-")
+
+// DoubleSignEvidence proves that a validator sealed two different headers at
+// the same height. The headers are RLP encoded, which is the format expected
+// by the slash system contract.
+type DoubleSignEvidence struct {
+	Validator common.Address `json:"validator"`
+	Number    uint64         `json:"number"`
+	HeaderA   hexutil.Bytes  `json:"headerA"`
+	HeaderB   hexutil.Bytes  `json:"headerB"`
+}
+
+// evidenceKey = evidencePrefix + num (uint64 big endian) + validator
+func evidenceKey(number uint64, validator common.Address) []byte {
+	key := make([]byte, len(evidencePrefix)+8+common.AddressLength)
+	copy(key, evidencePrefix)
+	binary.BigEndian.PutUint64(key[len(evidencePrefix):], number)
+	copy(key[len(evidencePrefix)+8:], validator[:])
+	return key
+}
+
+// seenSealKey identifies a single seal of a validator at a given height.
+type seenSealKey struct {
+	validator common.Address
+	number    uint64
+}
+
+// recordSeal remembers the header sealed by the validator at its height and
+// stores double sign evidence if a different header was seen before.
+func (p *Parlia) recordSeal(validator common.Address, header *types.Header) {
+	key := seenSealKey{validator: validator, number: header.Number.Uint64()}
+	cached, ok := p.seenHeaders.Get(key)
+	if !ok {
+		p.seenHeaders.Add(key, header)
+		return
+	}
+	prev := cached.(*types.Header)
+	if prev.Hash() == header.Hash() {
+		return
+	}
+	blobA, err := rlp.EncodeToBytes(prev)
+	if err != nil {
+		log.Error("Failed to encode double sign header", "err", err)
+		return
+	}
+	blobB, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		log.Error("Failed to encode double sign header", "err", err)
+		return
+	}
+	evidence := &DoubleSignEvidence{
+		Validator: validator,
+		Number:    key.number,
+		HeaderA:   blobA,
+		HeaderB:   blobB,
+	}
+	log.Warn("Detected double sign", "validator", validator, "number", key.number, "hashA", prev.Hash(), "hashB", header.Hash())
+	if err := writeDoubleSignEvidence(p.db, evidence); err != nil {
+		log.Error("Failed to store double sign evidence", "err", err)
+	}
+}
+
+// writeDoubleSignEvidence inserts the evidence into the database.
+func writeDoubleSignEvidence(db ethdb.KeyValueWriter, evidence *DoubleSignEvidence) error {
+	blob, err := json.Marshal(evidence)
+	if err != nil {
+		return err
+	}
+	return db.Put(evidenceKey(evidence.Number, evidence.Validator), blob)
+}
+
+// ReadDoubleSignEvidences retrieves all double sign evidences recorded in the
+// database between the given heights (inclusive), ordered by height.
+func ReadDoubleSignEvidences(db ethdb.Iteratee, from, to uint64) ([]*DoubleSignEvidence, error) {
+	start := make([]byte, 8)
+	binary.BigEndian.PutUint64(start, from)
+
+	it := db.NewIterator(evidencePrefix, start)
+	defer it.Release()
+
+	var evidences []*DoubleSignEvidence
+	for it.Next() {
+		if len(it.Key()) != len(evidencePrefix)+8+common.AddressLength {
+			continue
+		}
+		if binary.BigEndian.Uint64(it.Key()[len(evidencePrefix):]) > to {
+			break
+		}
+		evidence := new(DoubleSignEvidence)
+		if err := json.Unmarshal(it.Value(), evidence); err != nil {
+			return nil, err
+		}
+		evidences = append(evidences, evidence)
+	}
+	return evidences, it.Error()
+}
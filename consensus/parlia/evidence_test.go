@@ -0,0 +1,107 @@
+package parlia
+
+import (
+	"math/big"
+	"testing"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestDoubleSignDetection(t *testing.T) {
+	seenHeaders, _ := lru.NewARC(inMemorySeenHeaders)
+	p := &Parlia{db: rawdb.NewMemoryDatabase(), seenHeaders: seenHeaders}
+
+	validator := randomAddress()
+	headerA := &types.Header{Number: big.NewInt(10), Coinbase: validator, Extra: []byte{0x01}}
+	headerB := &types.Header{Number: big.NewInt(10), Coinbase: validator, Extra: []byte{0x02}}
+	other := &types.Header{Number: big.NewInt(11), Coinbase: validator}
+
+	// Sealing the same header twice or distinct heights is not a double sign
+	p.recordSeal(validator, headerA)
+	p.recordSeal(validator, headerA)
+	p.recordSeal(validator, other)
+	evidences, err := ReadDoubleSignEvidences(p.db, 0, 100)
+	assert.NoError(t, err)
+	assert.Empty(t, evidences)
+
+	p.recordSeal(validator, headerB)
+	evidences, err = ReadDoubleSignEvidences(p.db, 0, 100)
+	assert.NoError(t, err)
+	if assert.Len(t, evidences, 1) {
+		assert.Equal(t, validator, evidences[0].Validator)
+		assert.Equal(t, uint64(10), evidences[0].Number)
+
+		decoded := new(types.Header)
+		assert.NoError(t, rlp.DecodeBytes(evidences[0].HeaderB, decoded))
+		assert.Equal(t, headerB.Hash(), decoded.Hash())
+	}
+	// Range filtering excludes evidences outside of the requested heights
+	evidences, err = ReadDoubleSignEvidences(p.db, 11, 100)
+	assert.NoError(t, err)
+	assert.Empty(t, evidences)
+}
+
+// Tests that the evidence range accepts block tags and rejects unknown blocks.
+func TestDoubleSignEvidencesRange(t *testing.T) {
+	seenHeaders, _ := lru.NewARC(inMemorySeenHeaders)
+	recentSnaps, _ := lru.NewARC(inMemorySnapshots)
+	p := &Parlia{chainConfig: params.TestChainConfig, db: rawdb.NewMemoryDatabase(), seenHeaders: seenHeaders, recentSnaps: recentSnaps}
+
+	// Build a chain of blocks #100-#105 sealed by distinct validators, with a
+	// double sign at every height. Block #102 is safe and #101 finalized.
+	chain := &testChainReader{headers: make(map[common.Hash]*types.Header)}
+	snap := &Snapshot{Validators: make(map[common.Address]struct{})}
+	for number := int64(100); number <= 105; number++ {
+		validator := randomAddress()
+		header := &types.Header{Number: big.NewInt(number), Coinbase: validator}
+		if chain.head != nil {
+			header.ParentHash = chain.head.Hash()
+		}
+		chain.headers[header.Hash()], chain.head = header, header
+		snap.Validators[validator] = struct{}{}
+
+		p.recordSeal(validator, header)
+		p.recordSeal(validator, &types.Header{Number: big.NewInt(number), Coinbase: validator, Extra: []byte{0x01}})
+	}
+	snap.Number, snap.Hash = chain.head.Number.Uint64(), chain.head.Hash()
+	recentSnaps.Add(snap.Hash, snap)
+
+	api := &API{chain: chain, parlia: p}
+	blockNumber := func(n rpc.BlockNumber) *rpc.BlockNumber { return &n }
+
+	tests := []struct {
+		from  rpc.BlockNumber
+		to    *rpc.BlockNumber
+		fail  bool
+		first uint64
+		count int
+	}{
+		{from: 100, to: nil, first: 100, count: 6},
+		{from: 100, to: blockNumber(rpc.SafeBlockNumber), first: 100, count: 3},
+		{from: rpc.FinalizedBlockNumber, to: blockNumber(103), first: 101, count: 3},
+		{from: rpc.LatestBlockNumber, to: nil, first: 105, count: 1},
+		{from: rpc.PendingBlockNumber, to: blockNumber(rpc.PendingBlockNumber), first: 105, count: 1},
+		{from: rpc.SafeBlockNumber, to: blockNumber(rpc.FinalizedBlockNumber), fail: true},
+		{from: 100, to: blockNumber(110), fail: true},
+		{from: 110, to: nil, fail: true},
+	}
+	for i, tt := range tests {
+		evidences, err := api.GetDoubleSignEvidences(tt.from, tt.to)
+		if tt.fail {
+			assert.Error(t, err, "test %d", i)
+			continue
+		}
+		assert.NoError(t, err, "test %d", i)
+		if assert.Len(t, evidences, tt.count, "test %d", i) {
+			assert.Equal(t, tt.first, evidences[0].Number, "test %d", i)
+		}
+	}
+}
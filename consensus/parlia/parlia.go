@@ -196,6 +196,7 @@ type Parlia struct {
 
 	recentSnaps *lru.ARCCache // Snapshots for recent block to speed up
 	signatures  *lru.ARCCache // Signatures of recent blocks to speed up mining
+	seenHeaders *lru.ARCCache // Headers sealed by each validator per height to detect double signs
 
 	signer types.Signer
 
@@ -237,6 +238,10 @@ func New(
 	if err != nil {
 		panic(err)
 	}
+	seenHeaders, err := lru.NewARC(inMemorySeenHeaders)
+	if err != nil {
+		panic(err)
+	}
 	vABI, err := abi.JSON(strings.NewReader(validatorSetABI))
 	if err != nil {
 		panic(err)
@@ -253,6 +258,7 @@ func New(
 		ethAPI:          ethAPI,
 		recentSnaps:     recentSnaps,
 		signatures:      signatures,
+		seenHeaders:     seenHeaders,
 		validatorSetABI: vABI,
 		slashABI:        sABI,
 		signer:          types.NewEIP155Signer(chainConfig.ChainID),
@@ -565,6 +571,7 @@ func (p *Parlia) verifySeal(chain consensus.ChainReader, header *types.Header, p
 	if _, ok := snap.Validators[signer]; !ok {
 		return errUnauthorizedValidator
 	}
+	p.recordSeal(signer, header)
 
 	for seen, recent := range snap.Recents {
 		if recent == signer {
@@ -870,6 +877,45 @@ func (p *Parlia) EnoughDistance(chain consensus.ChainReader, header *types.Heade
 	return snap.enoughDistance(p.val)
 }
 
+// GetSafeHeader returns the latest ancestor of the given header that has been
+// built upon by more than half of the validators.
+func (p *Parlia) GetSafeHeader(chain consensus.ChainReader, header *types.Header) *types.Header {
+	return p.confirmedHeader(chain, header, func(validators int) int { return validators/2 + 1 })
+}
+
+// GetFinalizedHeader returns the latest ancestor of the given header that has
+// been built upon by more than two thirds of the validators, so reverting it
+// would require a byzantine majority.
+func (p *Parlia) GetFinalizedHeader(chain consensus.ChainReader, header *types.Header) *types.Header {
+	return p.confirmedHeader(chain, header, func(validators int) int { return validators*2/3 + 1 })
+}
+
+// confirmedHeader walks back from the given header until the blocks seen so far
+// were sealed by the quorum of distinct validators, returning the block where
+// the quorum was reached.
+func (p *Parlia) confirmedHeader(chain consensus.ChainReader, header *types.Header, quorum func(int) int) *types.Header {
+	snap, err := p.snapshot(chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		log.Debug("Failed to retrieve snapshot for finality", "number", header.Number, "hash", header.Hash(), "err", err)
+		return nil
+	}
+	var (
+		need    = quorum(len(snap.Validators))
+		signers = make(map[common.Address]struct{})
+	)
+	for depth := 0; header != nil && depth < checkpointInterval; depth++ {
+		if header.Number.Uint64() == 0 {
+			return header
+		}
+		signers[header.Coinbase] = struct{}{}
+		if len(signers) >= need {
+			return header
+		}
+		header = chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	}
+	return nil
+}
+
 // CalcDifficulty is the difficulty adjustment algorithm. It returns the difficulty
 // that a new block should have based on the previous blocks in the chain and the
 // current signer.
@@ -958,6 +1004,71 @@ func (p *Parlia) getCurrentValidators(blockHash common.Hash) ([]common.Address,
 	return valz, nil
 }
 
+// getValidatorInfos reads the full validator set, including jailed
+// validators, from the validator contract state at the given block.
+func (p *Parlia) getValidatorInfos(blockHash common.Hash) (map[common.Address]ValidatorInfo, error) {
+	// block
+	blockNr := rpc.BlockNumberOrHashWithHash(blockHash, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	toAddress := common.HexToAddress(systemcontracts.ValidatorContract)
+	gas := (hexutil.Uint64)(uint64(math.MaxUint64 / 2))
+
+	return readValidatorInfos(p.validatorSetABI, func(data []byte) ([]byte, error) {
+		msgData := (hexutil.Bytes)(data)
+		return p.ethAPI.Call(ctx, ethapi.CallArgs{
+			Gas:  &gas,
+			To:   &toAddress,
+			Data: &msgData,
+		}, blockNr, nil)
+	})
+}
+
+// readValidatorInfos reads the entries of the currentValidatorSet array of the
+// validator contract one by one through the given call function. The contract
+// has no getter for the array length, so the set ends at the first index whose
+// lookup reverts, which a call reports as an empty result rather than an error.
+func readValidatorInfos(validatorSetABI abi.ABI, call func(data []byte) ([]byte, error)) (map[common.Address]ValidatorInfo, error) {
+	// method
+	method := "currentValidatorSet"
+
+	infos := make(map[common.Address]ValidatorInfo)
+	for i := int64(0); ; i++ {
+		data, err := validatorSetABI.Pack(method, big.NewInt(i))
+		if err != nil {
+			log.Error("Unable to pack tx for currentValidatorSet", "error", err)
+			return nil, err
+		}
+		result, err := call(data)
+		if err != nil {
+			return nil, err
+		}
+		if len(result) == 0 {
+			break
+		}
+		var out struct {
+			ConsensusAddress common.Address
+			FeeAddress       common.Address
+			BBCFeeAddress    common.Address
+			VotingPower      uint64
+			Jailed           bool
+			Incoming         *big.Int
+		}
+		if err := validatorSetABI.Unpack(&out, method, result); err != nil {
+			return nil, err
+		}
+		power := out.VotingPower
+		infos[out.ConsensusAddress] = ValidatorInfo{
+			Address:     out.ConsensusAddress,
+			VotingPower: &power,
+			Jailed:      out.Jailed,
+		}
+	}
+	return infos, nil
+}
+
 // slash spoiled validators
 func (p *Parlia) distributeIncoming(val common.Address, state *state.StateDB, header *types.Header, chain core.ChainContext,
 	txs *[]*types.Transaction, receipts *[]*types.Receipt, receivedTxs *[]*types.Transaction, usedGas *uint64, mining bool) error {
@@ -1,11 +1,20 @@
 package parlia
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
 	"math/rand"
+	"strings"
 	"testing"
 
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 func TestImpactOfValidatorOutOfService(t *testing.T) {
@@ -144,3 +153,133 @@ func randomAddress() common.Address {
 	rand.Read(addrBytes)
 	return common.BytesToAddress(addrBytes)
 }
+
+// Tests that the full validator set is read from a validator contract whose
+// out of range lookups revert without returning data.
+func TestReadValidatorInfos(t *testing.T) {
+	vABI, err := abi.JSON(strings.NewReader(validatorSetABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	validators := []common.Address{randomAddress(), randomAddress(), randomAddress()}
+	contract := func(data []byte) ([]byte, error) {
+		index := new(big.Int).SetBytes(data[4:]).Uint64()
+		if index >= uint64(len(validators)) {
+			return nil, nil
+		}
+		method := vABI.Methods["currentValidatorSet"]
+		return method.Outputs.Pack(validators[index], common.Address{}, common.Address{}, 100*(index+1), index == 1, big.NewInt(0))
+	}
+	infos, err := readValidatorInfos(vABI, contract)
+	if err != nil {
+		t.Fatalf("failed to read validator set: %v", err)
+	}
+	if len(infos) != len(validators) {
+		t.Fatalf("validator count mismatch: have %d, want %d", len(infos), len(validators))
+	}
+	for i, validator := range validators {
+		info, ok := infos[validator]
+		if !ok {
+			t.Fatalf("validator %d missing", i)
+		}
+		if *info.VotingPower != 100*uint64(i+1) || info.Jailed != (i == 1) {
+			t.Errorf("validator %d mismatch: power %d, jailed %v", i, *info.VotingPower, info.Jailed)
+		}
+	}
+	failure := errors.New("missing trie node")
+	if _, err := readValidatorInfos(vABI, func([]byte) ([]byte, error) { return nil, failure }); err != failure {
+		t.Errorf("call failure mismatch: have %v, want %v", err, failure)
+	}
+}
+
+// testChainReader serves the headers of a fake chain.
+type testChainReader struct {
+	consensus.ChainReader
+	headers map[common.Hash]*types.Header
+	head    *types.Header
+}
+
+func (c *testChainReader) CurrentHeader() *types.Header {
+	return c.head
+}
+
+func (c *testChainReader) GetHeaderByNumber(number uint64) *types.Header {
+	for _, header := range c.headers {
+		if header.Number.Uint64() == number {
+			return header
+		}
+	}
+	return nil
+}
+
+func (c *testChainReader) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header, ok := c.headers[hash]; ok && header.Number.Uint64() == number {
+		return header
+	}
+	return nil
+}
+
+// Tests that the safe and finalized blocks are the latest ones built upon by
+// more than half and more than two thirds of the validators respectively.
+func TestConfirmedHeader(t *testing.T) {
+	validators := make([]common.Address, 6)
+	for i := range validators {
+		validators[i] = randomAddress()
+	}
+	tests := []struct {
+		signers   []int // Validators sealing the chain, head first
+		safe      int   // Depth of the safe block from the head, -1 if none
+		finalized int   // Depth of the finalized block from the head, -1 if none
+	}{
+		// Four distinct signers are needed for safety and five for finality
+		{signers: []int{0, 1, 0, 2, 3, 4, 5, 0}, safe: 4, finalized: 5},
+		{signers: []int{0, 1, 2, 3, 4, 5}, safe: 3, finalized: 4},
+		// Fewer active validators than either threshold confirm nothing
+		{signers: []int{0, 1, 2, 3, 0, 1, 2, 3}, safe: 3, finalized: -1},
+		{signers: []int{0, 1, 2, 0, 1, 2, 0, 1}, safe: -1, finalized: -1},
+	}
+	for i, tt := range tests {
+		// Build the chain from its oldest block, which has no known parent
+		var (
+			chain  = &testChainReader{headers: make(map[common.Hash]*types.Header)}
+			parent common.Hash
+			blocks = make([]*types.Header, len(tt.signers))
+		)
+		for j := len(tt.signers) - 1; j >= 0; j-- {
+			header := &types.Header{
+				ParentHash: parent,
+				Number:     big.NewInt(int64(100 + len(tt.signers) - 1 - j)),
+				Coinbase:   validators[tt.signers[j]],
+			}
+			chain.headers[header.Hash()] = header
+			blocks[j], parent = header, header.Hash()
+		}
+		head := blocks[0]
+
+		snap := &Snapshot{Number: head.Number.Uint64(), Hash: head.Hash(), Validators: make(map[common.Address]struct{})}
+		for _, validator := range validators {
+			snap.Validators[validator] = struct{}{}
+		}
+		recentSnaps, _ := lru.NewARC(inMemorySnapshots)
+		recentSnaps.Add(head.Hash(), snap)
+		p := &Parlia{chainConfig: params.TestChainConfig, recentSnaps: recentSnaps}
+
+		for _, check := range []struct {
+			name  string
+			have  *types.Header
+			depth int
+		}{
+			{"safe", p.GetSafeHeader(chain, head), tt.safe},
+			{"finalized", p.GetFinalizedHeader(chain, head), tt.finalized},
+		} {
+			switch {
+			case check.depth < 0 && check.have != nil:
+				t.Errorf("test %d: %s block mismatch: have #%d, want none", i, check.name, check.have.Number)
+			case check.depth >= 0 && check.have == nil:
+				t.Errorf("test %d: %s block mismatch: have none, want #%d", i, check.name, blocks[check.depth].Number)
+			case check.depth >= 0 && check.have.Hash() != blocks[check.depth].Hash():
+				t.Errorf("test %d: %s block mismatch: have #%d, want #%d", i, check.name, check.have.Number, blocks[check.depth].Number)
+			}
+		}
+	}
+}
@@ -122,6 +122,11 @@ type CacheConfig struct {
 	TrieTimeLimit       time.Duration // Time limit after which to flush the current in-memory trie to disk
 	SnapshotLimit       int           // Memory allowance (MB) to use for caching snapshot entries in memory
 
+	TxLookupLimit     uint64           // Number of recent blocks to keep transaction indexes for (0 = all)
+	TxLookupAddresses []common.Address // Accounts whose transactions are indexed, sent or received (empty = all)
+
+	TokenTransferIndex bool // Whether to index the token transfers of executed blocks by account
+
 	SnapshotWait bool // Wait for snapshot construction on startup. TODO(karalabe): This is a dirty hack for testing, nuke it
 }
 
@@ -171,6 +176,9 @@ type BlockChain struct {
 	txLookupCache *lru.Cache     // Cache for the most recent transaction lookup data.
 	futureBlocks  *lru.Cache     // future blocks are blocks added for later processing
 
+	txLookupFilter map[common.Address]struct{} // Accounts whose transactions are indexed (nil = all)
+	txIndexTail    uint64                      // Oldest block whose transactions are indexed (atomic)
+
 	quit    chan struct{} // blockchain quit channel
 	running int32         // running must be called atomically
 	// procInterrupt must be atomically called
@@ -182,6 +190,7 @@ type BlockChain struct {
 	prefetcher Prefetcher // Block state prefetcher interface
 	processor  Processor  // Block transaction processor interface
 	vmConfig   vm.Config
+	liveTracer LiveTracer // Optional tracer notified of every imported block
 
 	badBlocks       *lru.Cache                     // Bad block cache
 	shouldPreserve  func(*types.Block) bool        // Function used to determine whether should preserve the given block.
@@ -308,6 +317,28 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	if bc.cacheConfig.SnapshotLimit > 0 {
 		bc.snaps = snapshot.New(bc.db, bc.stateCache.TrieDB(), bc.cacheConfig.SnapshotLimit, bc.CurrentBlock().Root(), !bc.cacheConfig.SnapshotWait)
 	}
+	// Set up the transaction index retention, dropping stale indexes in the background
+	if len(bc.cacheConfig.TxLookupAddresses) > 0 {
+		bc.txLookupFilter = make(map[common.Address]struct{})
+		for _, addr := range bc.cacheConfig.TxLookupAddresses {
+			bc.txLookupFilter[addr] = struct{}{}
+		}
+	}
+	bc.txIndexTail = rawdb.ReadTxIndexTail(bc.db)
+	if tail := bc.txIndexTail; tail > 0 {
+		// Dropped indexes are not regenerated if the retention limit is raised
+		var (
+			head  = bc.CurrentBlock().NumberU64()
+			limit = bc.cacheConfig.TxLookupLimit
+		)
+		if limit == 0 || head < limit || head-limit+1 < tail {
+			log.Warn("Transaction indexes of old blocks were dropped and stay missing", "tail", tail, "limit", limit)
+		}
+	}
+	if bc.cacheConfig.TxLookupLimit > 0 {
+		bc.wg.Add(1)
+		go bc.maintainTxIndex()
+	}
 	// Take ownership of this particular state
 	go bc.update()
 	return bc, nil
@@ -545,6 +576,15 @@ func (bc *BlockChain) Processor() Processor {
 	return bc.processor
 }
 
+// SetLiveTracer sets the tracer to be notified of the execution of every block
+// imported from now on, or nil to remove it.
+func (bc *BlockChain) SetLiveTracer(tracer LiveTracer) {
+	bc.chainmu.Lock()
+	defer bc.chainmu.Unlock()
+
+	bc.liveTracer = tracer
+}
+
 // State returns a new mutable state based on the current HEAD block.
 func (bc *BlockChain) State() (*state.StateDB, error) {
 	return bc.StateAt(bc.CurrentBlock().Root())
@@ -662,7 +702,7 @@ func (bc *BlockChain) writeHeadBlock(block *types.Block) {
 	// Add the block to the canonical chain number scheme and mark as the head
 	batch := bc.db.NewBatch()
 	rawdb.WriteCanonicalHash(batch, block.Hash(), block.NumberU64())
-	rawdb.WriteTxLookupEntries(batch, block)
+	bc.writeTxLookupEntries(batch, block)
 	rawdb.WriteHeadBlockHash(batch, block.Hash())
 
 	// If the block is better than our head or is on a different chain, force update heads
@@ -1165,7 +1205,7 @@ func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain [
 			}
 			// Flush data into ancient database.
 			size += rawdb.WriteAncientBlock(bc.db, block, receiptChain[i], bc.GetTd(block.Hash(), block.NumberU64()))
-			rawdb.WriteTxLookupEntries(batch, block)
+			bc.writeTxLookupEntries(batch, block)
 
 			stats.processed++
 		}
@@ -1240,7 +1280,7 @@ func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain [
 			// Write all the data out into the database
 			rawdb.WriteBody(batch, block.Hash(), block.NumberU64(), block.Body())
 			rawdb.WriteReceipts(batch, block.Hash(), block.NumberU64(), receiptChain[i])
-			rawdb.WriteTxLookupEntries(batch, block)
+			bc.writeTxLookupEntries(batch, block)
 
 			// Write everything belongs to the blocks into the database. So that
 			// we can ensure all components of body is completed(body, receipts,
@@ -1337,9 +1377,35 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 	bc.chainmu.Lock()
 	defer bc.chainmu.Unlock()
 
+	if bc.liveTracer != nil {
+		bc.traceLocalBlock(block)
+	}
 	return bc.writeBlockWithState(block, receipts, logs, state, emitHeadEvent)
 }
 
+// traceLocalBlock re-executes a locally produced block on top of its parent
+// state to notify the live tracer, as the miner assembles the block without it.
+// It expects the chain mutex to be held.
+func (bc *BlockChain) traceLocalBlock(block *types.Block) {
+	tracer := bc.liveTracer
+	tracer.OnBlockStart(block)
+
+	var err error
+	if parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1); parent == nil {
+		err = consensus.ErrUnknownAncestor
+	} else {
+		var statedb *state.StateDB
+		if statedb, err = state.New(parent.Root, bc.stateCache, nil); err == nil {
+			statedb.SetBalanceHook(tracer.OnBalanceChange)
+			_, _, _, err = bc.processor.Process(block, statedb, bc.vmConfig, tracer)
+		}
+	}
+	if err != nil {
+		log.Warn("Failed to trace mined block", "number", block.Number(), "hash", block.Hash(), "err", err)
+	}
+	tracer.OnBlockEnd(block, err)
+}
+
 // writeBlockWithState writes the block and all associated state to the database,
 // but is expects the chain mutex to be held.
 func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.Receipt, logs []*types.Log, state *state.StateDB, emitHeadEvent bool) (status WriteStatus, err error) {
@@ -1364,6 +1430,9 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	rawdb.WriteTd(blockBatch, block.Hash(), block.NumberU64(), externTd)
 	rawdb.WriteBlock(blockBatch, block)
 	rawdb.WriteReceipts(blockBatch, block.Hash(), block.NumberU64(), receipts)
+	if bc.cacheConfig.TokenTransferIndex {
+		rawdb.WriteTokenTransfers(blockBatch, block.Hash(), block.NumberU64(), receipts)
+	}
 	rawdb.WritePreimages(blockBatch, state.Preimages())
 	if err := blockBatch.Write(); err != nil {
 		log.Crit("Failed to write block into disk", "err", err)
@@ -1719,8 +1788,15 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 		}
 		// Process block using the parent state as reference point
 		substart := time.Now()
-		receipts, logs, usedGas, err := bc.processor.Process(block, statedb, bc.vmConfig)
+		if bc.liveTracer != nil {
+			bc.liveTracer.OnBlockStart(block)
+			statedb.SetBalanceHook(bc.liveTracer.OnBalanceChange)
+		}
+		receipts, logs, usedGas, err := bc.processor.Process(block, statedb, bc.vmConfig, bc.liveTracer)
 		if err != nil {
+			if bc.liveTracer != nil {
+				bc.liveTracer.OnBlockEnd(block, err)
+			}
 			bc.reportBlock(block, receipts, err)
 			atomic.StoreUint32(&followupInterrupt, 1)
 			return it.index, err
@@ -1741,7 +1817,12 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 
 		// Validate the state using the default validator
 		substart = time.Now()
-		if err := bc.validator.ValidateState(block, statedb, receipts, usedGas); err != nil {
+		err = bc.validator.ValidateState(block, statedb, receipts, usedGas)
+		if bc.liveTracer != nil {
+			statedb.SetBalanceHook(nil)
+			bc.liveTracer.OnBlockEnd(block, err)
+		}
+		if err != nil {
 			bc.reportBlock(block, receipts, err)
 			atomic.StoreUint32(&followupInterrupt, 1)
 			return it.index, err
@@ -2111,6 +2192,109 @@ func (bc *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 	return nil
 }
 
+// writeTxLookupEntries indexes the transactions of the block, skipping blocks
+// past the retention limit and transactions not matching the account filter.
+func (bc *BlockChain) writeTxLookupEntries(db ethdb.KeyValueWriter, block *types.Block) {
+	if block.NumberU64() < atomic.LoadUint64(&bc.txIndexTail) {
+		return
+	}
+	if bc.txLookupFilter == nil {
+		rawdb.WriteTxLookupEntries(db, block)
+		return
+	}
+	signer := types.MakeSigner(bc.chainConfig, block.Number())
+	for _, tx := range block.Transactions() {
+		if !bc.indexedTx(signer, tx) {
+			continue
+		}
+		rawdb.WriteTxLookupEntry(db, tx.Hash(), block.NumberU64())
+	}
+}
+
+// indexedTx reports whether the transaction is sent from or to one of the
+// accounts whose transactions are indexed.
+func (bc *BlockChain) indexedTx(signer types.Signer, tx *types.Transaction) bool {
+	if to := tx.To(); to != nil {
+		if _, ok := bc.txLookupFilter[*to]; ok {
+			return true
+		}
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return false
+	}
+	_, ok := bc.txLookupFilter[from]
+	return ok
+}
+
+// maintainTxIndex drops the transaction indexes of the blocks falling out of
+// the retention window each time the chain head moves.
+func (bc *BlockChain) maintainTxIndex() {
+	defer bc.wg.Done()
+
+	headCh := make(chan ChainHeadEvent, 1)
+	sub := bc.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	bc.unindexTxs(bc.CurrentBlock().NumberU64())
+	for {
+		select {
+		case head := <-headCh:
+			bc.unindexTxs(head.Block.NumberU64())
+		case <-sub.Err():
+			return
+		case <-bc.quit:
+			return
+		}
+	}
+}
+
+// unindexTxs removes the transaction indexes of all blocks older than the
+// retention limit relative to the given head.
+func (bc *BlockChain) unindexTxs(head uint64) {
+	if head < bc.cacheConfig.TxLookupLimit {
+		return
+	}
+	var (
+		tail  = atomic.LoadUint64(&bc.txIndexTail)
+		limit = head - bc.cacheConfig.TxLookupLimit + 1
+		start = time.Now()
+		batch = bc.db.NewBatch()
+	)
+	if tail >= limit {
+		return
+	}
+	for number := tail; number < limit; number++ {
+		// Leave the remainder to the next run if the chain is shutting down
+		if bc.getProcInterrupt() {
+			limit = number
+			break
+		}
+		if block := rawdb.ReadBlock(bc.db, rawdb.ReadCanonicalHash(bc.db, number), number); block != nil {
+			for _, tx := range block.Transactions() {
+				rawdb.DeleteTxLookupEntry(batch, tx.Hash())
+			}
+		}
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			rawdb.WriteTxIndexTail(batch, number+1)
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed to unindex transactions", "err", err)
+			}
+			batch.Reset()
+			atomic.StoreUint64(&bc.txIndexTail, number+1)
+		}
+	}
+	rawdb.WriteTxIndexTail(batch, limit)
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to unindex transactions", "err", err)
+	}
+	atomic.StoreUint64(&bc.txIndexTail, limit)
+
+	if limit-tail > 1 {
+		log.Info("Unindexed transactions", "blocks", limit-tail, "tail", limit, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+}
+
 func (bc *BlockChain) update() {
 	futureTimer := time.NewTicker(5 * time.Second)
 	defer futureTimer.Stop()
@@ -17,6 +17,7 @@
 package core
 
 import (
+	"crypto/ecdsa"
 	"fmt"
 	"io/ioutil"
 	"math/big"
@@ -24,6 +25,7 @@ import (
 	"os"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -148,7 +150,7 @@ func testBlockChainImport(chain types.Blocks, blockchain *BlockChain) error {
 		if err != nil {
 			return err
 		}
-		receipts, _, usedGas, err := blockchain.processor.Process(block, statedb, vm.Config{})
+		receipts, _, usedGas, err := blockchain.processor.Process(block, statedb, vm.Config{}, nil)
 		if err != nil {
 			blockchain.reportBlock(block, receipts, err)
 			return err
@@ -2882,3 +2884,67 @@ func TestInitThenFailCreateContract(t *testing.T) {
 		}
 	}
 }
+
+// Tests that transaction indexes are only kept for the most recent blocks and
+// for the configured accounts.
+func TestTransactionIndexRetention(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		key2, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		addr2   = crypto.PubkeyToAddress(key2.PublicKey)
+		gspec   = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc: GenesisAlloc{
+				addr1: {Balance: big.NewInt(10000000000000)},
+				addr2: {Balance: big.NewInt(10000000000000)},
+			},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewEIP155Signer(gspec.Config.ChainID)
+	)
+	// Every block contains a transfer from both accounts
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 10, func(i int, gen *BlockGen) {
+		for _, key := range []*ecdsa.PrivateKey{key1, key2} {
+			from := crypto.PubkeyToAddress(key.PublicKey)
+			tx, err := types.SignTx(types.NewTransaction(gen.TxNonce(from), common.Address{0x01}, big.NewInt(1), params.TxGas, nil, nil), signer, key)
+			if err != nil {
+				t.Fatalf("failed to create tx: %v", err)
+			}
+			gen.AddTx(tx)
+		}
+	})
+	cacheConfig := &CacheConfig{
+		TrieCleanLimit:    256,
+		TrieDirtyLimit:    256,
+		TrieTimeLimit:     5 * time.Minute,
+		TxLookupLimit:     4,
+		TxLookupAddresses: []common.Address{addr1},
+	}
+	blockchain, _ := NewBlockChain(db, cacheConfig, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	defer blockchain.Stop()
+
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	// Unindexing happens in the background, wait for it to reach the head
+	for i := 0; atomic.LoadUint64(&blockchain.txIndexTail) != 7; i++ {
+		if i == 100 {
+			t.Fatalf("transaction index tail mismatch: have %d, want 7", atomic.LoadUint64(&blockchain.txIndexTail))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, block := range blocks {
+		for _, tx := range block.Transactions() {
+			from, _ := types.Sender(signer, tx)
+			indexed := rawdb.ReadTxLookupEntry(db, tx.Hash()) != nil
+			if want := from == addr1 && block.NumberU64() >= 7; indexed != want {
+				t.Errorf("block %d, sender %x: index presence mismatch: have %v, want %v", block.NumberU64(), from, indexed, want)
+			}
+		}
+	}
+	if tail := rawdb.ReadTxIndexTail(db); tail != 7 {
+		t.Fatalf("stored transaction index tail mismatch: have %d, want 7", tail)
+	}
+}
@@ -245,25 +245,35 @@ func SetupGenesisBlockWithOverride(db ethdb.Database, genesis *Genesis, override
 }
 
 func (g *Genesis) configOrDefault(ghash common.Hash) *params.ChainConfig {
-	switch {
-	case g != nil:
+	if g != nil {
 		return g.Config
-	case ghash == params.MainnetGenesisHash:
+	}
+	if config := BuiltinChainConfig(ghash); config != nil {
+		return config
+	}
+	return params.AllEthashProtocolChanges
+}
+
+// BuiltinChainConfig returns the chain config bundled with this binary for the
+// network with the given genesis hash, or nil if the network is unknown.
+func BuiltinChainConfig(ghash common.Hash) *params.ChainConfig {
+	switch ghash {
+	case params.MainnetGenesisHash:
 		return params.MainnetChainConfig
-	case ghash == params.RopstenGenesisHash:
+	case params.RopstenGenesisHash:
 		return params.RopstenChainConfig
-	case ghash == params.RinkebyGenesisHash:
+	case params.RinkebyGenesisHash:
 		return params.RinkebyChainConfig
-	case ghash == params.GoerliGenesisHash:
+	case params.GoerliGenesisHash:
 		return params.GoerliChainConfig
-	case ghash == params.BSCGenesisHash:
+	case params.BSCGenesisHash:
 		return params.BSCChainConfig
-	case ghash == params.ChapelGenesisHash:
+	case params.ChapelGenesisHash:
 		return params.ChapelChainConfig
-	case ghash == params.RialtoGenesisHash:
+	case params.RialtoGenesisHash:
 		return params.RialtoChainConfig
 	default:
-		return params.AllEthashProtocolChanges
+		return nil
 	}
 }
 
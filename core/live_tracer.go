@@ -0,0 +1,113 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// LiveTracer is notified of the execution of every block imported into the
+// chain, allowing external indexers to follow balance changes, logs and token
+// transfers without modifying the EVM.
+//
+// Callbacks are invoked synchronously from the block import path, so they must
+// be fast and must not retain or modify the passed objects. Blocks rejected
+// during validation are reported with an error in OnBlockEnd, in which case all
+// changes seen since the matching OnBlockStart must be discarded.
+//
+// Blocks are reported when they are executed, whether imported or mined
+// locally, not when they become canonical. Blocks executed on a side chain are
+// reported too, while side chain blocks stored without execution are only
+// reported once a reorg makes them canonical. Reorgs themselves are not
+// signalled, tracers needing the canonical chain must follow the chain head
+// events and drop the data of blocks that were reorged out.
+type LiveTracer interface {
+	// OnBlockStart is called before the transactions of a block are executed.
+	OnBlockStart(block *types.Block)
+
+	// OnTransactionStart is called before a transaction of the block is
+	// executed. System transactions applied by the consensus engine are not
+	// reported, but their balance changes are.
+	OnTransactionStart(index int, tx *types.Transaction, from common.Address)
+
+	// OnBalanceChange is called whenever the balance of an account changes,
+	// including changes reverted by a failing call and block rewards.
+	OnBalanceChange(addr common.Address, prev, new *big.Int)
+
+	// OnTransactionEnd is called after a transaction was executed with its
+	// receipt, which holds the emitted logs, or the error it failed with.
+	OnTransactionEnd(receipt *types.Receipt, err error)
+
+	// OnBlockEnd is called after the block was executed and validated, with
+	// the error rejecting it if any.
+	OnBlockEnd(block *types.Block, err error)
+}
+
+// LiveTracerConstructor creates a live tracer from its user supplied JSON
+// configuration, which may be empty.
+type LiveTracerConstructor func(config json.RawMessage) (LiveTracer, error)
+
+var (
+	liveTracersLock sync.RWMutex
+	liveTracers     = make(map[string]LiveTracerConstructor)
+)
+
+// RegisterLiveTracer makes a live tracer available by the given name. It is
+// meant to be called from the init function of the package implementing the
+// tracer, which is then linked into the node binary. It panics if a tracer
+// with the same name is already registered.
+func RegisterLiveTracer(name string, constructor LiveTracerConstructor) {
+	liveTracersLock.Lock()
+	defer liveTracersLock.Unlock()
+
+	if _, ok := liveTracers[name]; ok {
+		panic(fmt.Sprintf("live tracer %q already registered", name))
+	}
+	liveTracers[name] = constructor
+}
+
+// NewLiveTracer creates the live tracer registered by the given name.
+func NewLiveTracer(name string, config json.RawMessage) (LiveTracer, error) {
+	liveTracersLock.RLock()
+	constructor, ok := liveTracers[name]
+	liveTracersLock.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown live tracer %q, available: %v", name, LiveTracers())
+	}
+	return constructor(config)
+}
+
+// LiveTracers returns the sorted names of all registered live tracers.
+func LiveTracers() []string {
+	liveTracersLock.RLock()
+	defer liveTracersLock.RUnlock()
+
+	names := make([]string, 0, len(liveTracers))
+	for name := range liveTracers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,186 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// testLiveTracer records the callbacks it receives.
+type testLiveTracer struct {
+	blocks   []uint64
+	txs      []common.Address
+	receipts int
+	balances map[common.Address]*big.Int
+	failed   int
+}
+
+func (t *testLiveTracer) OnBlockStart(block *types.Block) {
+	t.blocks = append(t.blocks, block.NumberU64())
+}
+
+func (t *testLiveTracer) OnTransactionStart(index int, tx *types.Transaction, from common.Address) {
+	t.txs = append(t.txs, from)
+}
+
+func (t *testLiveTracer) OnBalanceChange(addr common.Address, prev, new *big.Int) {
+	t.balances[addr] = new
+}
+
+func (t *testLiveTracer) OnTransactionEnd(receipt *types.Receipt, err error) {
+	if err == nil && receipt != nil {
+		t.receipts++
+	}
+}
+
+func (t *testLiveTracer) OnBlockEnd(block *types.Block, err error) {
+	if err != nil {
+		t.failed++
+	}
+}
+
+// Tests that a registered live tracer is notified of the blocks, transactions
+// and balance changes of imported blocks.
+func TestLiveTracer(t *testing.T) {
+	RegisterLiveTracer("test", func(config json.RawMessage) (LiveTracer, error) {
+		return &testLiveTracer{balances: make(map[common.Address]*big.Int)}, nil
+	})
+	if _, err := NewLiveTracer("unknown", nil); err == nil {
+		t.Fatalf("unknown live tracer created")
+	}
+	tracer, err := NewLiveTracer("test", nil)
+	if err != nil {
+		t.Fatalf("failed to create live tracer: %v", err)
+	}
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		theAddr = common.Address{1}
+		heir    = common.Address{2}
+		suicide = crypto.CreateAddress(address, 2)
+		gspec   = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{address: {Balance: big.NewInt(1000000000)}},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewEIP155Signer(gspec.Config.ChainID)
+	)
+	blockchain, _ := NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	defer blockchain.Stop()
+	blockchain.SetLiveTracer(tracer)
+
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 2, func(i int, block *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(address), theAddr, big.NewInt(1000), 21000, big.NewInt(1), nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block.AddTx(tx)
+
+		// Deploy a contract with value in the second block, which self-destructs
+		// in its constructor, moving its balance to the heir
+		if i == 1 {
+			code := append(append([]byte{byte(vm.PUSH20)}, heir.Bytes()...), byte(vm.SELFDESTRUCT))
+			tx, err := types.SignTx(types.NewContractCreation(block.TxNonce(address), big.NewInt(500), 100000, big.NewInt(1), code), signer, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			block.AddTx(tx)
+		}
+	})
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	recorded := tracer.(*testLiveTracer)
+	if len(recorded.blocks) != 2 || recorded.blocks[0] != 1 || recorded.blocks[1] != 2 {
+		t.Errorf("traced blocks mismatch: have %v, want [1 2]", recorded.blocks)
+	}
+	if len(recorded.txs) != 3 || recorded.txs[0] != address || recorded.receipts != 3 {
+		t.Errorf("traced transactions mismatch: have %v with %d receipts", recorded.txs, recorded.receipts)
+	}
+	if recorded.failed != 0 {
+		t.Errorf("traced block failures mismatch: have %d, want 0", recorded.failed)
+	}
+	// Re-executing a block outside of the import, like the tracing APIs do, must
+	// not notify the tracer
+	statedb, _ := blockchain.StateAt(blocks[0].Root())
+	if _, _, _, err := blockchain.Processor().Process(blocks[1], statedb, vm.Config{}, nil); err != nil {
+		t.Fatalf("failed to re-execute block: %v", err)
+	}
+	if len(recorded.txs) != 3 {
+		t.Errorf("re-executed transactions traced: have %d, want 3", len(recorded.txs))
+	}
+	state, _ := blockchain.State()
+	for _, addr := range []common.Address{address, theAddr, heir, suicide, blocks[1].Coinbase()} {
+		if have, want := recorded.balances[addr], state.GetBalance(addr); have == nil || have.Cmp(want) != 0 {
+			t.Errorf("traced balance of %x mismatch: have %v, want %v", addr, have, want)
+		}
+	}
+}
+
+// Tests that blocks produced locally and written directly with their state, as
+// the miner does, are reported to the live tracer.
+func TestLiveTracerMinedBlocks(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		theAddr = common.Address{1}
+		gspec   = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{address: {Balance: big.NewInt(1000000000)}},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewEIP155Signer(gspec.Config.ChainID)
+		tracer  = &testLiveTracer{balances: make(map[common.Address]*big.Int)}
+	)
+	blockchain, _ := NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	defer blockchain.Stop()
+	blockchain.SetLiveTracer(tracer)
+
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 1, func(i int, block *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(address), theAddr, big.NewInt(1000), 21000, big.NewInt(1), nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block.AddTx(tx)
+	})
+	statedb, _ := blockchain.State()
+	receipts, logs, _, err := blockchain.Processor().Process(blocks[0], statedb, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to execute block: %v", err)
+	}
+	if _, err := blockchain.WriteBlockWithState(blocks[0], receipts, logs, statedb, false); err != nil {
+		t.Fatalf("failed to write block: %v", err)
+	}
+	if len(tracer.blocks) != 1 || tracer.blocks[0] != 1 || len(tracer.txs) != 1 || tracer.receipts != 1 || tracer.failed != 0 {
+		t.Errorf("traced mined block mismatch: blocks %v, %d transactions, %d receipts, %d failures", tracer.blocks, len(tracer.txs), tracer.receipts, tracer.failed)
+	}
+	if have := tracer.balances[theAddr]; have == nil || have.Int64() != 1000 {
+		t.Errorf("traced balance mismatch: have %v, want 1000", have)
+	}
+}
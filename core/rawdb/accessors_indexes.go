@@ -17,6 +17,8 @@
 package rawdb
 
 import (
+	"bytes"
+	"encoding/binary"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -63,6 +65,14 @@ func WriteTxLookupEntries(db ethdb.KeyValueWriter, block *types.Block) {
 	}
 }
 
+// WriteTxLookupEntry stores the positional metadata of a single transaction,
+// enabling hash based lookups of selected transactions only.
+func WriteTxLookupEntry(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Put(txLookupKey(hash), new(big.Int).SetUint64(number).Bytes()); err != nil {
+		log.Crit("Failed to store transaction lookup entry", "err", err)
+	}
+}
+
 // DeleteTxLookupEntry removes all transaction data associated with a hash.
 func DeleteTxLookupEntry(db ethdb.KeyValueWriter, hash common.Hash) {
 	db.Delete(txLookupKey(hash))
@@ -129,3 +139,144 @@ func WriteBloomBits(db ethdb.KeyValueWriter, bit uint, section uint64, head comm
 		log.Crit("Failed to store bloom bits", "err", err)
 	}
 }
+
+// DeleteBloomBits removes all compressed bloom bits vectors belonging to the
+// given section range and bit index.
+func DeleteBloomBits(db ethdb.Database, bit uint, from uint64, to uint64) {
+	deleteBloomBitsRange(db, bloomBitsKey(bit, from, common.Hash{}), bloomBitsKey(bit, to, common.Hash{}))
+}
+
+// DeleteAllBloomBits removes the compressed bloom bits vectors of all bit
+// indexes and sections in a single pass over the database.
+func DeleteAllBloomBits(db ethdb.Database) {
+	deleteBloomBitsRange(db, bloomBitsPrefix, []byte{bloomBitsPrefix[0] + 1})
+}
+
+// deleteBloomBitsRange removes the bloom bits vectors in the [start, end) key
+// range, skipping the other entries sharing their prefix.
+func deleteBloomBitsRange(db ethdb.Database, start, end []byte) {
+	it := db.NewIterator(nil, start)
+	defer it.Release()
+
+	batch := db.NewBatch()
+	for it.Next() {
+		if bytes.Compare(it.Key(), end) >= 0 {
+			break
+		}
+		if len(it.Key()) != len(bloomBitsPrefix)+2+8+32 {
+			continue
+		}
+		batch.Delete(it.Key())
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed to delete bloom bits", "err", err)
+			}
+			batch.Reset()
+		}
+	}
+	if it.Error() != nil {
+		log.Crit("Failed to delete bloom bits", "err", it.Error())
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to delete bloom bits", "err", err)
+	}
+}
+
+// CompactBloomBits compacts the key range holding the bloom bits vectors.
+func CompactBloomBits(db ethdb.Compacter) error {
+	return db.Compact(bloomBitsPrefix, []byte{bloomBitsPrefix[0] + 1})
+}
+
+// TransferEventTopic is the topic of the Transfer(address,address,uint256) event
+// emitted by ERC-20 and BEP-20 tokens.
+var TransferEventTopic = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+// TokenTransfer is an ERC-20/BEP-20 token transfer, indexed for both accounts
+// involved. The positional fields are part of the database key.
+type TokenTransfer struct {
+	BlockNumber uint64      `rlp:"-"`
+	BlockHash   common.Hash `rlp:"-"`
+	LogIndex    uint        `rlp:"-"`
+	TxHash      common.Hash
+	Token       common.Address
+	From        common.Address
+	To          common.Address
+	Value       *big.Int
+}
+
+// WriteTokenTransfers indexes the token transfers logged by the receipts of a
+// block for the sender and the recipient. Transfers of ERC-721 tokens, which
+// index the token id as a third topic, are skipped.
+func WriteTokenTransfers(db ethdb.KeyValueWriter, hash common.Hash, number uint64, receipts types.Receipts) {
+	for _, receipt := range receipts {
+		for _, l := range receipt.Logs {
+			if len(l.Topics) != 3 || l.Topics[0] != TransferEventTopic || len(l.Data) != common.HashLength {
+				continue
+			}
+			transfer := TokenTransfer{
+				TxHash: l.TxHash,
+				Token:  l.Address,
+				From:   common.BytesToAddress(l.Topics[1].Bytes()),
+				To:     common.BytesToAddress(l.Topics[2].Bytes()),
+				Value:  new(big.Int).SetBytes(l.Data),
+			}
+			data, err := rlp.EncodeToBytes(transfer)
+			if err != nil {
+				log.Crit("Failed to encode token transfer", "err", err)
+			}
+			if err := db.Put(tokenTransferKey(transfer.From, number, hash, l.Index), data); err != nil {
+				log.Crit("Failed to store token transfer", "err", err)
+			}
+			if transfer.To != transfer.From {
+				if err := db.Put(tokenTransferKey(transfer.To, number, hash, l.Index), data); err != nil {
+					log.Crit("Failed to store token transfer", "err", err)
+				}
+			}
+		}
+	}
+}
+
+// ReadTokenTransfers retrieves at most limit token transfers sent or received
+// by the account in the canonical blocks of the given range (inclusive),
+// ordered by block and log index. Transfers of side chain blocks are skipped.
+func ReadTokenTransfers(db ethdb.Database, addr common.Address, from uint64, to uint64, limit int) []*TokenTransfer {
+	prefix := append(append([]byte{}, tokenTransferPrefix...), addr.Bytes()...)
+	it := db.NewIterator(prefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	var (
+		transfers []*TokenTransfer
+		canonical = make(map[uint64]common.Hash)
+	)
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(prefix)+8+common.HashLength+4 {
+			continue
+		}
+		number := binary.BigEndian.Uint64(key[len(prefix):])
+		if number > to {
+			break
+		}
+		hash := common.BytesToHash(key[len(prefix)+8 : len(prefix)+8+common.HashLength])
+		if _, ok := canonical[number]; !ok {
+			canonical[number] = ReadCanonicalHash(db, number)
+		}
+		if canonical[number] != hash {
+			continue
+		}
+		transfer := new(TokenTransfer)
+		if err := rlp.DecodeBytes(it.Value(), transfer); err != nil {
+			log.Error("Invalid token transfer RLP", "number", number, "hash", hash, "err", err)
+			continue
+		}
+		transfer.BlockNumber = number
+		transfer.BlockHash = hash
+		transfer.LogIndex = uint(binary.BigEndian.Uint32(key[len(key)-4:]))
+
+		transfers = append(transfers, transfer)
+		if limit > 0 && len(transfers) >= limit {
+			break
+		}
+	}
+	return transfers
+}
@@ -106,3 +106,94 @@ func TestLookupStorage(t *testing.T) {
 		})
 	}
 }
+
+// Tests that bloom bits vectors are deleted for the requested bit and section
+// range only.
+func TestDeleteBloomBits(t *testing.T) {
+	db := NewMemoryDatabase()
+	for bit := uint(0); bit < 2; bit++ {
+		for section := uint64(0); section < 4; section++ {
+			WriteBloomBits(db, bit, section, common.Hash{byte(section)}, []byte{0x01})
+		}
+	}
+	DeleteBloomBits(db, 0, 1, 3)
+
+	for bit := uint(0); bit < 2; bit++ {
+		for section := uint64(0); section < 4; section++ {
+			_, err := ReadBloomBits(db, bit, section, common.Hash{byte(section)})
+			if deleted := bit == 0 && section >= 1 && section < 3; deleted != (err != nil) {
+				t.Errorf("bit %d section %d: deleted mismatch: have %v, want %v", bit, section, err != nil, deleted)
+			}
+		}
+	}
+	// Deleting all vectors must leave the other entries sharing the prefix intact
+	WriteBloomBits(db, types.BloomBitLength-1, 0, common.Hash{}, []byte{0x01})
+	if err := db.Put(append(common.CopyBytes(bloomBitsPrefix), 0x01), []byte{0x01}); err != nil {
+		t.Fatal(err)
+	}
+	DeleteAllBloomBits(db)
+
+	for bit := uint(0); bit < types.BloomBitLength; bit += types.BloomBitLength - 1 {
+		for section := uint64(0); section < 4; section++ {
+			if _, err := ReadBloomBits(db, bit, section, common.Hash{byte(section)}); err == nil {
+				t.Errorf("bit %d section %d: not deleted", bit, section)
+			}
+		}
+	}
+	if ok, _ := db.Has(append(common.CopyBytes(bloomBitsPrefix), 0x01)); !ok {
+		t.Errorf("unrelated entry deleted")
+	}
+}
+
+// Tests that token transfers are indexed for both accounts and only returned
+// for canonical blocks.
+func TestTokenTransfers(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	var (
+		token    = common.Address{0xee}
+		alice    = common.Address{0x01}
+		bob      = common.Address{0x02}
+		canon    = common.Hash{0xaa}
+		side     = common.Hash{0xbb}
+		next     = common.Hash{0xcc}
+		transfer = func(from, to common.Address, amount int64, index uint) *types.Log {
+			return &types.Log{
+				Address: token,
+				Topics:  []common.Hash{TransferEventTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+				Data:    common.BigToHash(big.NewInt(amount)).Bytes(),
+				Index:   index,
+			}
+		}
+		nft = &types.Log{
+			Address: token,
+			Topics:  []common.Hash{TransferEventTopic, common.BytesToHash(alice.Bytes()), common.BytesToHash(bob.Bytes()), {0x01}},
+			Index:   2,
+		}
+	)
+	WriteCanonicalHash(db, canon, 1)
+	WriteCanonicalHash(db, next, 2)
+
+	WriteTokenTransfers(db, canon, 1, types.Receipts{{Logs: []*types.Log{transfer(alice, bob, 1, 0), transfer(bob, bob, 2, 1), nft}}})
+	WriteTokenTransfers(db, side, 1, types.Receipts{{Logs: []*types.Log{transfer(alice, bob, 3, 0)}}})
+	WriteTokenTransfers(db, next, 2, types.Receipts{{Logs: []*types.Log{transfer(bob, alice, 4, 0)}}})
+
+	transfers := ReadTokenTransfers(db, bob, 0, 10, 0)
+	if len(transfers) != 3 {
+		t.Fatalf("transfer count mismatch: have %d, want 3", len(transfers))
+	}
+	for i, want := range []int64{1, 2, 4} {
+		if transfers[i].Value.Int64() != want || transfers[i].Token != token {
+			t.Errorf("transfer %d mismatch: have value %v of %x, want %d", i, transfers[i].Value, transfers[i].Token, want)
+		}
+	}
+	if transfers[1].BlockHash != canon || transfers[1].LogIndex != 1 || transfers[2].BlockNumber != 2 {
+		t.Errorf("transfer position mismatch: %+v, %+v", transfers[1], transfers[2])
+	}
+	if transfers := ReadTokenTransfers(db, alice, 2, 2, 0); len(transfers) != 1 || transfers[0].From != bob {
+		t.Errorf("ranged transfers mismatch: %v", transfers)
+	}
+	if transfers := ReadTokenTransfers(db, bob, 0, 10, 2); len(transfers) != 2 {
+		t.Errorf("limited transfer count mismatch: have %d, want 2", len(transfers))
+	}
+}
@@ -41,6 +41,9 @@ var (
 	// fastTrieProgressKey tracks the number of trie entries imported during fast sync.
 	fastTrieProgressKey = []byte("TrieSync")
 
+	// txIndexTailKey tracks the oldest block whose transactions have been indexed.
+	txIndexTailKey = []byte("TransactionIndexTail")
+
 	// snapshotRootKey tracks the hash of the last snapshot.
 	snapshotRootKey = []byte("SnapshotRoot")
 
@@ -60,6 +63,7 @@ var (
 	bloomBitsPrefix       = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
 	SnapshotAccountPrefix = []byte("a") // SnapshotAccountPrefix + account hash -> account trie value
 	SnapshotStoragePrefix = []byte("o") // SnapshotStoragePrefix + account hash + storage hash -> storage trie value
+	tokenTransferPrefix   = []byte("x") // tokenTransferPrefix + address + num (uint64 big endian) + hash + log index (uint32 big endian) -> token transfer
 
 	preimagePrefix = []byte("secure-key-")      // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-") // config prefix for the db
@@ -153,6 +157,18 @@ func txLookupKey(hash common.Hash) []byte {
 	return append(txLookupPrefix, hash.Bytes()...)
 }
 
+// tokenTransferKey = tokenTransferPrefix + address + num (uint64 big endian) + hash + log index (uint32 big endian)
+func tokenTransferKey(addr common.Address, number uint64, hash common.Hash, index uint) []byte {
+	key := append(append(tokenTransferKeyPrefix(addr, number), hash.Bytes()...), make([]byte, 4)...)
+	binary.BigEndian.PutUint32(key[len(key)-4:], uint32(index))
+	return key
+}
+
+// tokenTransferKeyPrefix = tokenTransferPrefix + address + num (uint64 big endian)
+func tokenTransferKeyPrefix(addr common.Address, number uint64) []byte {
+	return append(append(append([]byte{}, tokenTransferPrefix...), addr.Bytes()...), encodeBlockNumber(number)...)
+}
+
 // accountSnapshotKey = SnapshotAccountPrefix + hash
 func accountSnapshotKey(hash common.Hash) []byte {
 	return append(SnapshotAccountPrefix, hash.Bytes()...)
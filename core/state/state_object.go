@@ -408,6 +408,9 @@ func (s *stateObject) SetBalance(amount *big.Int) {
 }
 
 func (s *stateObject) setBalance(amount *big.Int) {
+	if s.db.balanceHook != nil {
+		s.db.balanceHook(s.address, s.data.Balance, amount)
+	}
 	s.data.Balance = amount
 }
 
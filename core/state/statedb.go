@@ -101,6 +101,10 @@ type StateDB struct {
 	validRevisions []revision
 	nextRevisionId int
 
+	// Optional callback invoked on every balance modification, including the
+	// ones restored by reverting to a snapshot.
+	balanceHook func(addr common.Address, prev, new *big.Int)
+
 	// Measurements gathered during execution for debugging purposes
 	AccountReads         time.Duration
 	AccountHashes        time.Duration
@@ -115,6 +119,14 @@ type StateDB struct {
 	SnapshotCommits      time.Duration
 }
 
+// SetBalanceHook sets a callback to be invoked whenever the balance of an
+// account is modified, or nil to remove it. Reverted modifications are
+// reported as a change back to the previous balance. The hook is not carried
+// over to copies of the state.
+func (s *StateDB) SetBalanceHook(hook func(addr common.Address, prev, new *big.Int)) {
+	s.balanceHook = hook
+}
+
 // Create a new state from a given trie.
 func New(root common.Hash, db Database, snaps *snapshot.Tree) (*StateDB, error) {
 	tr, err := db.OpenTrie(root)
@@ -443,7 +455,7 @@ func (s *StateDB) Suicide(addr common.Address) bool {
 		prevbalance: new(big.Int).Set(stateObject.Balance()),
 	})
 	stateObject.markSuicided()
-	stateObject.data.Balance = new(big.Int)
+	stateObject.setBalance(new(big.Int))
 
 	return true
 }
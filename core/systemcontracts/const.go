@@ -1,5 +1,7 @@
 package systemcontracts
 
+import "github.com/ethereum/go-ethereum/common"
+
 const (
 	// genesis contracts
 	ValidatorContract          = "0x0000000000000000000000000000000000001000"
@@ -13,3 +15,17 @@ const (
 	TokenManagerContract       = "0x0000000000000000000000000000000000001008"
 	CrossChainContract         = "0x0000000000000000000000000000000000002000"
 )
+
+// Names maps the addresses of the system contracts to their contract names.
+var Names = map[common.Address]string{
+	common.HexToAddress(ValidatorContract):          "ValidatorSet",
+	common.HexToAddress(SlashContract):              "SlashIndicator",
+	common.HexToAddress(SystemRewardContract):       "SystemReward",
+	common.HexToAddress(LightClientContract):        "TendermintLightClient",
+	common.HexToAddress(TokenHubContract):           "TokenHub",
+	common.HexToAddress(RelayerIncentivizeContract): "RelayerIncentivize",
+	common.HexToAddress(RelayerHubContract):         "RelayerHub",
+	common.HexToAddress(GovHubContract):             "GovHub",
+	common.HexToAddress(TokenManagerContract):       "TokenManager",
+	common.HexToAddress(CrossChainContract):         "CrossChain",
+}
@@ -280,18 +280,7 @@ func UpgradeBuildInSystemContract(config *params.ChainConfig, blockNumber *big.I
 	if config == nil || blockNumber == nil || statedb == nil {
 		return
 	}
-	var network string
-	switch GenesisHash {
-	/* Add mainnet genesis hash */
-	case params.BSCGenesisHash:
-		network = mainNet
-	case params.ChapelGenesisHash:
-		network = chapelNet
-	case params.RialtoGenesisHash:
-		network = rialtoNet
-	default:
-		network = defaultNet
-	}
+	network := networkName(GenesisHash)
 
 	logger := log.New("system-contract-upgrade", network)
 	if config.IsOnRamanujan(blockNumber) {
@@ -311,6 +300,56 @@ func UpgradeBuildInSystemContract(config *params.ChainConfig, blockNumber *big.I
 	*/
 }
 
+// networkName returns the network the upgrades are bundled for, based on the
+// genesis hash of the chain.
+func networkName(genesisHash common.Hash) string {
+	switch genesisHash {
+	/* Add mainnet genesis hash */
+	case params.BSCGenesisHash:
+		return mainNet
+	case params.ChapelGenesisHash:
+		return chapelNet
+	case params.RialtoGenesisHash:
+		return rialtoNet
+	default:
+		return defaultNet
+	}
+}
+
+// Hardforks lists the names of the hardforks upgrading system contracts, in
+// activation order.
+var Hardforks = []string{"ramanujan", "niels", "mirror"}
+
+// ExpectedCode returns the bytecode the system contracts are expected to have
+// once all upgrades up to and including the given hardfork were applied on the
+// chain with the given genesis. Contracts that were never upgraded by then are
+// not included, as their code comes from the genesis.
+func ExpectedCode(hardfork string, genesisHash common.Hash) (map[common.Address][]byte, error) {
+	upgrades := []map[string]*Upgrade{ramanujanUpgrade, nielsUpgrade, mirrorUpgrade}
+
+	network := networkName(genesisHash)
+	for i, name := range Hardforks {
+		if name != hardfork {
+			continue
+		}
+		codes := make(map[common.Address][]byte)
+		for _, upgrade := range upgrades[:i+1] {
+			if upgrade[network] == nil {
+				continue
+			}
+			for _, cfg := range upgrade[network].Configs {
+				code, err := hex.DecodeString(cfg.Code)
+				if err != nil {
+					return nil, fmt.Errorf("failed to decode code of contract %s: %v", cfg.ContractAddr.String(), err)
+				}
+				codes[cfg.ContractAddr] = code
+			}
+		}
+		return codes, nil
+	}
+	return nil, fmt.Errorf("unknown hardfork %q, want one of %v", hardfork, Hardforks)
+}
+
 func applySystemContractUpgrade(upgrade *Upgrade, blockNumber *big.Int, statedb *state.StateDB, logger log.Logger) {
 	if upgrade == nil {
 		logger.Info("Empty upgrade config", "height", blockNumber.String())
@@ -0,0 +1,187 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	// ErrAltLaneDisabled is returned if a transaction is submitted to the alt
+	// lane while it has no slots configured.
+	ErrAltLaneDisabled = errors.New("alt transaction lane disabled")
+
+	// ErrAltLaneFull is returned if a new transaction is submitted to the alt
+	// lane while all of its slots are taken.
+	ErrAltLaneFull = errors.New("alt transaction lane full")
+
+	// ErrAltSenderNotAllowed is returned if a transaction is submitted to the
+	// alt lane by a sender not configured to use it.
+	ErrAltSenderNotAllowed = errors.New("sender not allowed in alt transaction lane")
+)
+
+// altLane is a small, separate pool of transactions submitted by bundlers and
+// other designated senders. Its transactions are never propagated and are not
+// subject to the main pool's pricing rules: a transaction simply replaces any
+// previous one with the same sender and nonce.
+type altLane struct {
+	slots    int                                // Maximum number of transactions in the lane
+	senders  map[common.Address]struct{}        // Senders allowed to use the lane
+	lifetime time.Duration                      // Maximum time a non-executable transaction is kept
+	txs      map[common.Address]*txSortedMap    // Transactions in the lane by sender
+	all      map[common.Hash]*types.Transaction // All transactions in the lane by hash
+	added    map[common.Hash]time.Time          // Time each transaction entered the lane
+}
+
+// newAltLane creates an alt transaction lane with the given number of slots,
+// open to the given senders only.
+func newAltLane(slots int, senders []common.Address, lifetime time.Duration) *altLane {
+	lane := &altLane{
+		slots:    slots,
+		senders:  make(map[common.Address]struct{}),
+		lifetime: lifetime,
+		txs:      make(map[common.Address]*txSortedMap),
+		all:      make(map[common.Hash]*types.Transaction),
+		added:    make(map[common.Hash]time.Time),
+	}
+	for _, sender := range senders {
+		lane.senders[sender] = struct{}{}
+	}
+	return lane
+}
+
+// add inserts the transaction of the sender into the lane, replacing any
+// previous transaction with the same nonce.
+func (l *altLane) add(from common.Address, tx *types.Transaction) error {
+	if l.slots == 0 || len(l.senders) == 0 {
+		return ErrAltLaneDisabled
+	}
+	if _, ok := l.senders[from]; !ok {
+		return ErrAltSenderNotAllowed
+	}
+	if l.all[tx.Hash()] != nil {
+		return ErrAlreadyKnown
+	}
+	list := l.txs[from]
+	if list == nil {
+		list = newTxSortedMap()
+	}
+	old := list.Get(tx.Nonce())
+	if old == nil && len(l.all) >= l.slots {
+		return ErrAltLaneFull
+	}
+	if old != nil {
+		delete(l.all, old.Hash())
+		delete(l.added, old.Hash())
+	}
+	list.Put(tx)
+	l.txs[from] = list
+	l.all[tx.Hash()] = tx
+	l.added[tx.Hash()] = time.Now()
+	return nil
+}
+
+// reset drops all transactions which became stale with the new state, and the
+// ones which could not be executed for longer than the lifetime of the lane.
+func (l *altLane) reset(statedb *state.StateDB) {
+	for from, list := range l.txs {
+		nonce := statedb.GetNonce(from)
+		for _, tx := range list.Forward(nonce) {
+			delete(l.all, tx.Hash())
+			delete(l.added, tx.Hash())
+		}
+		for _, tx := range list.Flatten() {
+			if tx.Nonce() == nonce {
+				nonce++
+				continue
+			}
+			if time.Since(l.added[tx.Hash()]) > l.lifetime {
+				list.Remove(tx.Nonce())
+				delete(l.all, tx.Hash())
+				delete(l.added, tx.Hash())
+			}
+		}
+		if list.Len() == 0 {
+			delete(l.txs, from)
+		}
+	}
+}
+
+// pending returns the transactions of each sender which are executable on top
+// of the given state, ordered by nonce.
+func (l *altLane) pending(statedb *state.StateDB) map[common.Address]types.Transactions {
+	pending := make(map[common.Address]types.Transactions)
+	for from, list := range l.txs {
+		nonce := statedb.GetNonce(from)
+
+		var txs types.Transactions
+		for _, tx := range list.Flatten() {
+			if tx.Nonce() != nonce {
+				break
+			}
+			txs = append(txs, tx)
+			nonce++
+		}
+		if len(txs) > 0 {
+			pending[from] = txs
+		}
+	}
+	return pending
+}
+
+// AddAlt validates a transaction and inserts it into the alt lane, keeping it
+// apart from the main pool and its replacement rules.
+func (pool *TxPool) AddAlt(tx *types.Transaction) error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if pool.all.Get(tx.Hash()) != nil {
+		return ErrAlreadyKnown
+	}
+	if err := pool.validateTx(tx, false); err != nil {
+		return err
+	}
+	from, _ := types.Sender(pool.signer, tx) // already validated
+	return pool.alt.add(from, tx)
+}
+
+// AltPending retrieves the executable transactions of the alt lane, grouped by
+// origin account and sorted by nonce.
+func (pool *TxPool) AltPending() map[common.Address]types.Transactions {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return pool.alt.pending(pool.currentState)
+}
+
+// AltContent retrieves all transactions of the alt lane, grouped by origin
+// account and sorted by nonce.
+func (pool *TxPool) AltContent() map[common.Address]types.Transactions {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	content := make(map[common.Address]types.Transactions)
+	for from, list := range pool.alt.txs {
+		content[from] = list.Flatten()
+	}
+	return content
+}
@@ -727,6 +727,37 @@ func TestTransactionGapFilling(t *testing.T) {
 	}
 }
 
+// Tests that the pool content of a single account contains both its pending
+// and queued transactions, and nothing from other accounts.
+func TestTransactionContentFrom(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	other, _ := crypto.GenerateKey()
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	pool.currentState.AddBalance(account, big.NewInt(1000000))
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(other.PublicKey), big.NewInt(1000000))
+
+	pool.AddRemotesSync([]*types.Transaction{
+		transaction(0, 100000, key),
+		transaction(1, 100000, key),
+		transaction(3, 100000, key),
+		transaction(0, 100000, other),
+	})
+	pending, queued := pool.ContentFrom(account)
+	if len(pending) != 2 {
+		t.Errorf("pending transactions mismatched: have %d, want %d", len(pending), 2)
+	}
+	if len(queued) != 1 {
+		t.Errorf("queued transactions mismatched: have %d, want %d", len(queued), 1)
+	}
+	if pending, queued = pool.ContentFrom(common.Address{}); len(pending) != 0 || len(queued) != 0 {
+		t.Errorf("unknown account content mismatched: have %d/%d, want 0/0", len(pending), len(queued))
+	}
+}
+
 // Tests that if the transaction count belonging to a single account goes above
 // some threshold, the higher transactions are dropped to prevent DOS attacks.
 func TestTransactionQueueAccountLimiting(t *testing.T) {
@@ -1914,3 +1945,116 @@ func benchmarkPoolBatchInsert(b *testing.B, size int) {
 		pool.AddRemotes(batch)
 	}
 }
+
+// Tests that transactions in the alt lane are kept apart from the main pool,
+// replace each other regardless of price and are capped by the lane size.
+func TestTransactionAltLane(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	key, _ := crypto.GenerateKey()
+	account := crypto.PubkeyToAddress(key.PublicKey)
+
+	config := testTxPoolConfig
+	config.AltSlots = 3
+	config.AltSenders = []common.Address{account}
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	pool.currentState.AddBalance(account, big.NewInt(1000000000))
+
+	// Add a gapped sequence of transactions and make sure only the executable
+	// ones are pending, without touching the main pool
+	for _, nonce := range []uint64{0, 1, 3} {
+		if err := pool.AddAlt(pricedTransaction(nonce, 100000, big.NewInt(10), key)); err != nil {
+			t.Fatalf("tx %d: failed to add alt transaction: %v", nonce, err)
+		}
+	}
+	if pool.all.Count() != 0 {
+		t.Fatalf("main pool size mismatch: have %d, want %d", pool.all.Count(), 0)
+	}
+	if pending := pool.AltPending()[account]; len(pending) != 2 {
+		t.Fatalf("pending alt transactions mismatch: have %d, want %d", len(pending), 2)
+	}
+	// Replace a transaction with a cheaper one and overflow the lane
+	cheaper := pricedTransaction(1, 100000, big.NewInt(1), key)
+	if err := pool.AddAlt(cheaper); err != nil {
+		t.Fatalf("failed to replace alt transaction: %v", err)
+	}
+	if pending := pool.AltPending()[account]; pending[1].Hash() != cheaper.Hash() {
+		t.Errorf("alt transaction not replaced")
+	}
+	if err := pool.AddAlt(pricedTransaction(2, 100000, big.NewInt(10), key)); err != ErrAltLaneFull {
+		t.Errorf("overflowing alt lane error mismatch: have %v, want %v", err, ErrAltLaneFull)
+	}
+	// Advance the nonce and make sure stale transactions are dropped
+	pool.currentState.SetNonce(account, 2)
+	pool.alt.reset(pool.currentState)
+
+	if content := pool.AltContent()[account]; len(content) != 1 || content[0].Nonce() != 3 {
+		t.Errorf("alt lane content mismatch after reset: have %d transactions", len(content))
+	}
+	if err := pool.AddAlt(pricedTransaction(1, 100000, big.NewInt(10), key)); err != ErrNonceTooLow {
+		t.Errorf("stale alt transaction error mismatch: have %v, want %v", err, ErrNonceTooLow)
+	}
+}
+
+// Tests that the alt lane is closed to senders not allowed to use it, and that
+// non-executable transactions flooding it are evicted after their lifetime.
+func TestTransactionAltLaneFlooding(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	key, _ := crypto.GenerateKey()
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	other, _ := crypto.GenerateKey()
+	statedb.AddBalance(account, big.NewInt(1000000000))
+	statedb.AddBalance(crypto.PubkeyToAddress(other.PublicKey), big.NewInt(1000000000))
+
+	// The lane is disabled by default, even for configured senders
+	config := testTxPoolConfig
+	config.AltSenders = []common.Address{account}
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	if err := pool.AddAlt(pricedTransaction(0, 100000, big.NewInt(1), key)); err != ErrAltLaneDisabled {
+		t.Errorf("default alt lane error mismatch: have %v, want %v", err, ErrAltLaneDisabled)
+	}
+	pool.Stop()
+
+	config.AltSlots = 4
+	pool = NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	if err := pool.AddAlt(pricedTransaction(0, 100000, big.NewInt(1), other)); err != ErrAltSenderNotAllowed {
+		t.Errorf("foreign sender error mismatch: have %v, want %v", err, ErrAltSenderNotAllowed)
+	}
+	// Flood the lane with gapped transactions, none of which can execute
+	for nonce := uint64(10); nonce < 14; nonce++ {
+		if err := pool.AddAlt(pricedTransaction(nonce, 100000, big.NewInt(1), key)); err != nil {
+			t.Fatalf("tx %d: failed to add alt transaction: %v", nonce, err)
+		}
+	}
+	if err := pool.AddAlt(pricedTransaction(0, 100000, big.NewInt(1), key)); err != ErrAltLaneFull {
+		t.Fatalf("flooded alt lane error mismatch: have %v, want %v", err, ErrAltLaneFull)
+	}
+	// Fresh transactions survive a reset, expired ones are evicted
+	pool.alt.reset(pool.currentState)
+	if content := pool.AltContent()[account]; len(content) != 4 {
+		t.Fatalf("alt lane content mismatch before expiry: have %d, want %d", len(content), 4)
+	}
+	for hash := range pool.alt.added {
+		pool.alt.added[hash] = time.Now().Add(-2 * config.Lifetime)
+	}
+	pool.alt.reset(pool.currentState)
+	if content := pool.AltContent()[account]; len(content) != 0 {
+		t.Fatalf("alt lane content mismatch after expiry: have %d, want %d", len(content), 0)
+	}
+	if err := pool.AddAlt(pricedTransaction(0, 100000, big.NewInt(1), key)); err != nil {
+		t.Errorf("failed to add alt transaction after eviction: %v", err)
+	}
+}
@@ -46,6 +46,7 @@ type Prefetcher interface {
 type Processor interface {
 	// Process processes the state changes according to the Ethereum rules by running
 	// the transaction messages using the statedb and applying any rewards to both
-	// the processor (coinbase) and any included uncles.
-	Process(block *types.Block, statedb *state.StateDB, cfg vm.Config) (types.Receipts, []*types.Log, uint64, error)
+	// the processor (coinbase) and any included uncles. The optional tracer is
+	// notified of the execution of every transaction.
+	Process(block *types.Block, statedb *state.StateDB, cfg vm.Config, tracer LiveTracer) (types.Receipts, []*types.Log, uint64, error)
 }
@@ -75,6 +75,80 @@ func (api *PublicEthereumAPI) ChainId() hexutil.Uint64 {
 	return (hexutil.Uint64)(chainID.Uint64())
 }
 
+// maxTokenTransfers is the maximum number of token transfers returned by a
+// single eth_getTokenTransfers request.
+const maxTokenTransfers = 10000
+
+// RPCTokenTransfer is an ERC-20/BEP-20 token transfer as returned over RPC.
+type RPCTokenTransfer struct {
+	BlockNumber     hexutil.Uint64 `json:"blockNumber"`
+	BlockHash       common.Hash    `json:"blockHash"`
+	TransactionHash common.Hash    `json:"transactionHash"`
+	LogIndex        hexutil.Uint   `json:"logIndex"`
+	Token           common.Address `json:"token"`
+	From            common.Address `json:"from"`
+	To              common.Address `json:"to"`
+	Value           *hexutil.Big   `json:"value"`
+}
+
+// GetTokenTransfers returns the ERC-20/BEP-20 token transfers sent or received
+// by the account in the given block range (inclusive). It requires the node to
+// run with the token transfer index enabled, which only covers the blocks
+// executed since.
+func (api *PublicEthereumAPI) GetTokenTransfers(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber) ([]*RPCTokenTransfer, error) {
+	if !api.e.config.TokenTransferIndex {
+		return nil, errors.New("token transfer index disabled")
+	}
+	from, err := api.tokenTransferBound(ctx, fromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.tokenTransferBound(ctx, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, fmt.Errorf("end block (#%d) needs to come after start block (#%d)", to, from)
+	}
+	transfers := rawdb.ReadTokenTransfers(api.e.ChainDb(), address, from, to, maxTokenTransfers+1)
+	if len(transfers) > maxTokenTransfers {
+		return nil, fmt.Errorf("too many token transfers, maximum %d: narrow the block range", maxTokenTransfers)
+	}
+	results := make([]*RPCTokenTransfer, 0, len(transfers))
+	for _, transfer := range transfers {
+		results = append(results, &RPCTokenTransfer{
+			BlockNumber:     hexutil.Uint64(transfer.BlockNumber),
+			BlockHash:       transfer.BlockHash,
+			TransactionHash: transfer.TxHash,
+			LogIndex:        hexutil.Uint(transfer.LogIndex),
+			Token:           transfer.Token,
+			From:            transfer.From,
+			To:              transfer.To,
+			Value:           (*hexutil.Big)(transfer.Value),
+		})
+	}
+	return results, nil
+}
+
+// tokenTransferBound resolves a block tag bounding a token transfer query to a
+// height. Pending transfers are never indexed, so pending resolves to the head.
+func (api *PublicEthereumAPI) tokenTransferBound(ctx context.Context, number rpc.BlockNumber) (uint64, error) {
+	if number >= 0 {
+		return uint64(number), nil
+	}
+	if number == rpc.PendingBlockNumber {
+		number = rpc.LatestBlockNumber
+	}
+	header, err := api.e.APIBackend.HeaderByNumber(ctx, number)
+	if err != nil {
+		return 0, err
+	}
+	if header == nil {
+		return 0, fmt.Errorf("block %v not found", number)
+	}
+	return header.Number.Uint64(), nil
+}
+
 // PublicMinerAPI provides an API to control the miner.
 // It offers only methods that operate on data that pose no security risk when it is publicly accessible.
 type PublicMinerAPI struct {
@@ -166,6 +240,32 @@ func NewPrivateAdminAPI(eth *Ethereum) *PrivateAdminAPI {
 	return &PrivateAdminAPI{eth: eth}
 }
 
+// GasPriceBounds returns the floor and the ceiling of the suggested gas price.
+// The floor is omitted if there is none.
+func (api *PrivateAdminAPI) GasPriceBounds() map[string]*hexutil.Big {
+	min, max := api.eth.APIBackend.gpo.PriceBounds()
+	bounds := map[string]*hexutil.Big{"ceiling": (*hexutil.Big)(max)}
+	if min != nil {
+		bounds["floor"] = (*hexutil.Big)(min)
+	}
+	return bounds
+}
+
+// SetGasPriceBounds updates the floor and the ceiling of the suggested gas
+// price. A missing floor removes it, a missing ceiling resets it to default.
+func (api *PrivateAdminAPI) SetGasPriceBounds(floor, ceiling *hexutil.Big) (bool, error) {
+	if err := api.eth.APIBackend.gpo.SetPriceBounds((*big.Int)(floor), (*big.Int)(ceiling)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// PeerStats returns the propagation and health statistics of the recently seen
+// peers, along with the number of disconnects per reason.
+func (api *PrivateAdminAPI) PeerStats() *PeerStatsResult {
+	return api.eth.protocolManager.peerStats.stats()
+}
+
 // ExportChain exports the current blockchain into a local file,
 // or a range of blocks if first and last are non-nil
 func (api *PrivateAdminAPI) ExportChain(file string, first *uint64, last *uint64) (bool, error) {
@@ -285,14 +385,12 @@ func (api *PublicDebugAPI) DumpBlock(blockNr rpc.BlockNumber) (state.Dump, error
 		_, stateDb := api.eth.miner.Pending()
 		return stateDb.RawDump(false, false, true), nil
 	}
-	var block *types.Block
-	if blockNr == rpc.LatestBlockNumber {
-		block = api.eth.blockchain.CurrentBlock()
-	} else {
-		block = api.eth.blockchain.GetBlockByNumber(uint64(blockNr))
+	block, err := api.eth.APIBackend.BlockByNumber(context.Background(), blockNr)
+	if err != nil {
+		return state.Dump{}, err
 	}
 	if block == nil {
-		return state.Dump{}, fmt.Errorf("block #%d not found", blockNr)
+		return state.Dump{}, fmt.Errorf("block %v not found", blockNr)
 	}
 	stateDb, err := api.eth.BlockChain().StateAt(block.Root())
 	if err != nil {
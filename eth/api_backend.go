@@ -23,6 +23,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -42,6 +43,7 @@ type EthAPIBackend struct {
 	extRPCEnabled bool
 	eth           *Ethereum
 	gpo           *gasprice.Oracle
+	archive       *rpc.Client
 }
 
 // ChainConfig returns the active chain configuration.
@@ -68,6 +70,9 @@ func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumb
 	if number == rpc.LatestBlockNumber {
 		return b.eth.blockchain.CurrentBlock().Header(), nil
 	}
+	if number == rpc.SafeBlockNumber || number == rpc.FinalizedBlockNumber {
+		return confirmedHeader(b.eth.engine, b.eth.blockchain, b.eth.blockchain.CurrentHeader(), number)
+	}
 	return b.eth.blockchain.GetHeaderByNumber(uint64(number)), nil
 }
 
@@ -102,9 +107,29 @@ func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumbe
 	if number == rpc.LatestBlockNumber {
 		return b.eth.blockchain.CurrentBlock(), nil
 	}
+	if number == rpc.SafeBlockNumber || number == rpc.FinalizedBlockNumber {
+		header, err := confirmedHeader(b.eth.engine, b.eth.blockchain, b.eth.blockchain.CurrentHeader(), number)
+		if header == nil || err != nil {
+			return nil, err
+		}
+		return b.eth.blockchain.GetBlock(header.Hash(), header.Number.Uint64()), nil
+	}
 	return b.eth.blockchain.GetBlockByNumber(uint64(number)), nil
 }
 
+// confirmedHeader resolves the safe and finalized block tags relative to the
+// given chain head. Only PoSA engines are able to tell which blocks are final.
+func confirmedHeader(engine consensus.Engine, chain consensus.ChainReader, head *types.Header, number rpc.BlockNumber) (*types.Header, error) {
+	posa, ok := engine.(consensus.PoSA)
+	if !ok {
+		return nil, errors.New("safe and finalized blocks are not supported by the consensus engine")
+	}
+	if number == rpc.SafeBlockNumber {
+		return posa.GetSafeHeader(chain, head), nil
+	}
+	return posa.GetFinalizedHeader(chain, head), nil
+}
+
 func (b *EthAPIBackend) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
 	return b.eth.blockchain.GetBlockByHash(hash), nil
 }
@@ -224,6 +249,20 @@ func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	return b.eth.txPool.AddLocal(signedTx)
 }
 
+// SendPrivateTx adds the transaction to the pool without ever propagating it
+// to peers. It is added as a remote transaction so that it is not journaled
+// and rebroadcast after a restart.
+func (b *EthAPIBackend) SendPrivateTx(ctx context.Context, signedTx *types.Transaction) error {
+	b.eth.protocolManager.MarkPrivateTx(signedTx.Hash())
+	return b.eth.txPool.AddRemote(signedTx)
+}
+
+// SendAltTx adds the transaction to the separate alt lane of the pool, which
+// is never propagated and not subject to the main pool's replacement rules.
+func (b *EthAPIBackend) SendAltTx(ctx context.Context, signedTx *types.Transaction) error {
+	return b.eth.txPool.AddAlt(signedTx)
+}
+
 func (b *EthAPIBackend) GetPoolTransactions() (types.Transactions, error) {
 	pending, err := b.eth.txPool.Pending()
 	if err != nil {
@@ -257,6 +296,14 @@ func (b *EthAPIBackend) TxPoolContent() (map[common.Address]types.Transactions,
 	return b.eth.TxPool().Content()
 }
 
+func (b *EthAPIBackend) TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
+	return b.eth.TxPool().ContentFrom(addr)
+}
+
+func (b *EthAPIBackend) TxPoolAltContent() map[common.Address]types.Transactions {
+	return b.eth.TxPool().AltContent()
+}
+
 func (b *EthAPIBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
 	return b.eth.TxPool().SubscribeNewTxsEvent(ch)
 }
@@ -273,6 +320,10 @@ func (b *EthAPIBackend) SuggestPrice(ctx context.Context) (*big.Int, error) {
 	return b.gpo.SuggestPrice(ctx)
 }
 
+func (b *EthAPIBackend) FeeHistory(ctx context.Context, blocks int, lastBlock rpc.BlockNumber, percentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error) {
+	return b.gpo.FeeHistory(ctx, blocks, lastBlock, percentiles)
+}
+
 func (b *EthAPIBackend) ChainDb() ethdb.Database {
 	return b.eth.ChainDb()
 }
@@ -293,6 +344,10 @@ func (b *EthAPIBackend) RPCGasCap() *big.Int {
 	return b.eth.config.RPCGasCap
 }
 
+func (b *EthAPIBackend) ArchiveClient() *rpc.Client {
+	return b.archive
+}
+
 func (b *EthAPIBackend) BloomStatus() (uint64, uint64) {
 	sections, _, _ := b.eth.bloomIndexer.Sections()
 	return params.BloomBitsBlocks, sections
@@ -0,0 +1,333 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// maxContractStatsBlocks is the maximum number of blocks re-executed by a
+	// single contract statistics request.
+	maxContractStatsBlocks = 4096
+
+	// defaultContractStatsLimit is the number of contracts reported by default,
+	// ordered by the gas used by transactions sent to them.
+	defaultContractStatsLimit = 100
+)
+
+// ContractStatsConfig holds the extra parameters of a contract statistics
+// request.
+type ContractStatsConfig struct {
+	Limit  *int    // Maximum number of contracts to report
+	Reexec *uint64 // Number of blocks to re-execute for missing historical state
+}
+
+// ContractStats summarises the gas, storage growth and access pattern of a
+// single contract over a block range.
+type ContractStats struct {
+	Address       common.Address `json:"address"`
+	Transactions  uint64         `json:"transactions"`  // Transactions sent directly to the contract
+	GasUsed       uint64         `json:"gasUsed"`       // Gas used by the transactions sent to the contract
+	Calls         uint64         `json:"calls"`         // Internal message calls into the contract
+	StorageReads  uint64         `json:"storageReads"`  // Executed SLOAD operations
+	StorageWrites uint64         `json:"storageWrites"` // Executed SSTORE operations, excluding reverted ones
+	SlotsAccessed int            `json:"slotsAccessed"` // Distinct storage slots read or written
+	SlotsCreated  uint64         `json:"slotsCreated"`  // Storage slots set from zero to a non-zero value, excluding reverted writes
+	SlotsCleared  uint64         `json:"slotsCleared"`  // Storage slots reset from a non-zero value to zero, excluding reverted writes
+	StorageGrowth int64          `json:"storageGrowth"` // Net number of storage slots added
+
+	slots map[common.Hash]struct{}
+}
+
+// ContractStatsResult is the result of a contract statistics request.
+type ContractStatsResult struct {
+	From         uint64           `json:"from"`
+	To           uint64           `json:"to"`
+	Transactions uint64           `json:"transactions"`
+	GasUsed      uint64           `json:"gasUsed"`
+	Contracts    []*ContractStats `json:"contracts"`
+}
+
+// storageWrites counts the storage writes of a contract within a call frame.
+type storageWrites struct {
+	writes  uint64
+	created uint64
+	cleared uint64
+}
+
+// contractStatsTracer is an EVM tracer accumulating the storage accesses and
+// internal calls of each contract.
+//
+// Storage writes are buffered per call frame and only accounted once the frame
+// returned successfully, so that writes rolled back by a revert are not counted.
+type contractStatsTracer struct {
+	stats  map[common.Address]*ContractStats
+	frames []map[common.Address]*storageWrites // Pending writes of the active call frames
+}
+
+func (t *contractStatsTracer) contract(addr common.Address) *ContractStats {
+	stats := t.stats[addr]
+	if stats == nil {
+		stats = &ContractStats{Address: addr, slots: make(map[common.Hash]struct{})}
+		t.stats[addr] = stats
+	}
+	return stats
+}
+
+// merge moves the pending writes of a returned call frame into its parent, or
+// into the contract statistics if it was the outermost frame.
+func (t *contractStatsTracer) merge(frame map[common.Address]*storageWrites) {
+	if len(t.frames) == 0 {
+		for addr, pending := range frame {
+			stats := t.contract(addr)
+			stats.StorageWrites += pending.writes
+			stats.SlotsCreated += pending.created
+			stats.SlotsCleared += pending.cleared
+		}
+		return
+	}
+	parent := t.frames[len(t.frames)-1]
+	for addr, pending := range frame {
+		if parent[addr] == nil {
+			parent[addr] = new(storageWrites)
+		}
+		parent[addr].writes += pending.writes
+		parent[addr].created += pending.created
+		parent[addr].cleared += pending.cleared
+	}
+}
+
+func (t *contractStatsTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	t.frames = t.frames[:0]
+	return nil
+}
+
+func (t *contractStatsTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	// The first operation after an inner call returned finds the success flag
+	// (or the created address) of the call on top of the stack
+	for len(t.frames) > depth {
+		frame := t.frames[len(t.frames)-1]
+		t.frames = t.frames[:len(t.frames)-1]
+
+		if len(t.frames) > depth || (len(stack.Data()) > 0 && stack.Back(0).Sign() != 0) {
+			t.merge(frame)
+		}
+	}
+	for len(t.frames) < depth {
+		t.frames = append(t.frames, make(map[common.Address]*storageWrites))
+	}
+	if err != nil {
+		return nil
+	}
+	switch op {
+	case vm.SLOAD:
+		if len(stack.Data()) < 1 {
+			return nil
+		}
+		stats := t.contract(contract.Address())
+		stats.StorageReads++
+		stats.slots[common.BigToHash(stack.Back(0))] = struct{}{}
+
+	case vm.SSTORE:
+		if len(stack.Data()) < 2 {
+			return nil
+		}
+		var (
+			frame = t.frames[len(t.frames)-1]
+			slot  = common.BigToHash(stack.Back(0))
+			value = common.BigToHash(stack.Back(1))
+			prev  = env.StateDB.GetState(contract.Address(), slot)
+		)
+		t.contract(contract.Address()).slots[slot] = struct{}{}
+
+		pending := frame[contract.Address()]
+		if pending == nil {
+			pending = new(storageWrites)
+			frame[contract.Address()] = pending
+		}
+		pending.writes++
+
+		switch {
+		case prev == (common.Hash{}) && value != (common.Hash{}):
+			pending.created++
+		case prev != (common.Hash{}) && value == (common.Hash{}):
+			pending.cleared++
+		}
+
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		if len(stack.Data()) < 2 {
+			return nil
+		}
+		if target := common.BigToAddress(stack.Back(1)); env.StateDB.GetCodeSize(target) > 0 {
+			t.contract(target).Calls++
+		}
+	}
+	return nil
+}
+
+func (t *contractStatsTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+func (t *contractStatsTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	// Account the writes of the transaction unless it failed as a whole
+	for len(t.frames) > 0 {
+		frame := t.frames[len(t.frames)-1]
+		t.frames = t.frames[:len(t.frames)-1]
+
+		if err == nil {
+			t.merge(frame)
+		}
+	}
+	return nil
+}
+
+// ContractStats re-executes the blocks in the given range (inclusive) and
+// summarises the gas used, storage growth and storage access pattern of every
+// contract touched, ordered by the gas used by transactions sent to them.
+func (api *PrivateDebugAPI) ContractStats(ctx context.Context, start, end rpc.BlockNumber, config *ContractStatsConfig) (*ContractStatsResult, error) {
+	var from, to *types.Block
+
+	switch start {
+	case rpc.PendingBlockNumber, rpc.LatestBlockNumber:
+		from = api.eth.blockchain.CurrentBlock()
+	default:
+		from = api.eth.blockchain.GetBlockByNumber(uint64(start))
+	}
+	switch end {
+	case rpc.PendingBlockNumber, rpc.LatestBlockNumber:
+		to = api.eth.blockchain.CurrentBlock()
+	default:
+		to = api.eth.blockchain.GetBlockByNumber(uint64(end))
+	}
+	if from == nil {
+		return nil, fmt.Errorf("starting block #%d not found", start)
+	}
+	if to == nil {
+		return nil, fmt.Errorf("end block #%d not found", end)
+	}
+	if from.NumberU64() == 0 {
+		return nil, fmt.Errorf("genesis is not executable")
+	}
+	if from.NumberU64() > to.NumberU64() {
+		return nil, fmt.Errorf("end block (#%d) needs to come after start block (#%d)", end, start)
+	}
+	if blocks := to.NumberU64() - from.NumberU64() + 1; blocks > maxContractStatsBlocks {
+		return nil, fmt.Errorf("block range too large: %d blocks, maximum %d", blocks, maxContractStatsBlocks)
+	}
+	limit := defaultContractStatsLimit
+	if config != nil && config.Limit != nil {
+		limit = *config.Limit
+	}
+	reexec := defaultTraceReexec
+	if config != nil && config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	parent := api.eth.blockchain.GetBlock(from.ParentHash(), from.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent %#x not found", from.ParentHash())
+	}
+	statedb, err := api.computeStateDB(parent, reexec)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		result      = &ContractStatsResult{From: from.NumberU64(), To: to.NumberU64()}
+		tracer      = &contractStatsTracer{stats: make(map[common.Address]*ContractStats)}
+		chainConfig = api.eth.blockchain.Config()
+		logged      = time.Now()
+	)
+	for number := from.NumberU64(); number <= to.NumberU64(); number++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		block := api.eth.blockchain.GetBlockByNumber(number)
+		if block == nil {
+			return nil, fmt.Errorf("block #%d not found", number)
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Collecting contract statistics", "block", number, "remaining", to.NumberU64()-number)
+			logged = time.Now()
+		}
+		// Mutate the state according to the hard-fork specs, like block processing
+		if chainConfig.DAOForkSupport && chainConfig.DAOForkBlock != nil && chainConfig.DAOForkBlock.Cmp(block.Number()) == 0 {
+			misc.ApplyDAOHardFork(statedb)
+		}
+		systemcontracts.UpgradeBuildInSystemContract(chainConfig, block.Number(), statedb)
+
+		signer := types.MakeSigner(chainConfig, block.Number())
+		for i, tx := range block.Transactions() {
+			msg, _ := tx.AsMessage(signer)
+			vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain, nil)
+			if system, _ := api.systemTxInfo(tx, block.Header()); system {
+				collectSystemBalance(statedb, block.Coinbase())
+			}
+			statedb.Prepare(tx.Hash(), block.Hash(), i)
+
+			vmenv := vm.NewEVM(vmctx, statedb, chainConfig, vm.Config{Debug: true, Tracer: tracer})
+			_, gas, _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas()))
+			if err != nil {
+				return nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
+			}
+			statedb.Finalise(chainConfig.IsEIP158(block.Number()))
+
+			result.Transactions++
+			result.GasUsed += gas
+
+			target := msg.To()
+			if target == nil {
+				addr := crypto.CreateAddress(msg.From(), msg.Nonce())
+				target = &addr
+			}
+			if statedb.GetCodeSize(*target) > 0 {
+				stats := tracer.contract(*target)
+				stats.Transactions++
+				stats.GasUsed += gas
+			}
+		}
+	}
+	for _, stats := range tracer.stats {
+		stats.SlotsAccessed = len(stats.slots)
+		stats.StorageGrowth = int64(stats.SlotsCreated) - int64(stats.SlotsCleared)
+		result.Contracts = append(result.Contracts, stats)
+	}
+	sort.Slice(result.Contracts, func(i, j int) bool {
+		if result.Contracts[i].GasUsed != result.Contracts[j].GasUsed {
+			return result.Contracts[i].GasUsed > result.Contracts[j].GasUsed
+		}
+		return result.Contracts[i].StorageWrites > result.Contracts[j].StorageWrites
+	})
+	if limit > 0 && len(result.Contracts) > limit {
+		result.Contracts = result.Contracts[:limit]
+	}
+	return result, nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// deployCode wraps the runtime code of a contract into its creation code.
+func deployCode(runtime []byte) []byte {
+	return append([]byte{0x60, byte(len(runtime)), 0x60, 0x0c, 0x60, 0x00, 0x39, 0x60, byte(len(runtime)), 0x60, 0x00, 0xf3}, runtime...)
+}
+
+// callCode returns the code calling the given contract with all gas left and
+// discarding the result.
+func callCode(addr common.Address) []byte {
+	code := []byte{0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x73}
+	return append(append(code, addr.Bytes()...), 0x5a, 0xf1, 0x50)
+}
+
+// Tests that the storage accesses and gas of contracts are collected over the
+// re-executed blocks, ignoring the writes rolled back by reverts.
+func TestContractStats(t *testing.T) {
+	var (
+		signer   = types.HomesteadSigner{}
+		toggler  = crypto.CreateAddress(testBank, 0) // Flips slot 0 between zero and one
+		reverter = crypto.CreateAddress(testBank, 1) // Calls the toggler, sets slot 0 and reverts
+		caller   = crypto.CreateAddress(testBank, 2) // Calls the reverter and sets slot 0
+
+		togglerCode  = []byte{0x60, 0x00, 0x54, 0x15, 0x60, 0x00, 0x55, 0x00}
+		reverterCode = append(callCode(toggler), 0x60, 0x01, 0x60, 0x00, 0x55, 0x60, 0x00, 0x60, 0x00, 0xfd)
+		callerCode   = append(callCode(reverter), 0x60, 0x01, 0x60, 0x00, 0x55, 0x00)
+	)
+	pm, db := newTestProtocolManagerMust(t, downloader.FullSync, 2, func(i int, b *core.BlockGen) {
+		var txs []*types.Transaction
+		switch i {
+		case 0:
+			for _, code := range [][]byte{togglerCode, reverterCode, callerCode} {
+				txs = append(txs, types.NewContractCreation(b.TxNonce(testBank)+uint64(len(txs)), new(big.Int), 100000, big.NewInt(1), deployCode(code)))
+			}
+		case 1:
+			for _, to := range []common.Address{toggler, caller, reverter, toggler} {
+				txs = append(txs, types.NewTransaction(b.TxNonce(testBank)+uint64(len(txs)), to, new(big.Int), 100000, big.NewInt(1), nil))
+			}
+		}
+		for _, tx := range txs {
+			signed, err := types.SignTx(tx, signer, testBankKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b.AddTx(signed)
+		}
+	}, nil)
+	defer pm.Stop()
+
+	api := NewPrivateDebugAPI(&Ethereum{blockchain: pm.blockchain, engine: pm.blockchain.Engine(), chainDb: db})
+	result, err := api.ContractStats(context.Background(), 2, 2, nil)
+	if err != nil {
+		t.Fatalf("failed to collect contract statistics: %v", err)
+	}
+	block := pm.blockchain.GetBlockByNumber(2)
+	receipts := pm.blockchain.GetReceiptsByHash(block.Hash())
+	if receipts[2].Status != types.ReceiptStatusFailed {
+		t.Fatalf("transaction to the reverter succeeded")
+	}
+	if result.Transactions != 4 || result.GasUsed != block.GasUsed() {
+		t.Errorf("totals mismatch: have %d transactions using %d gas, want 4 using %d", result.Transactions, result.GasUsed, block.GasUsed())
+	}
+	want := map[common.Address]ContractStats{
+		toggler: {
+			Transactions:  2,
+			GasUsed:       receipts[0].GasUsed + receipts[3].GasUsed,
+			Calls:         2,
+			StorageReads:  4,
+			StorageWrites: 2,
+			SlotsAccessed: 1,
+			SlotsCreated:  1,
+			SlotsCleared:  1,
+		},
+		reverter: {
+			Transactions:  1,
+			GasUsed:       receipts[2].GasUsed,
+			Calls:         1,
+			SlotsAccessed: 1,
+		},
+		caller: {
+			Transactions:  1,
+			GasUsed:       receipts[1].GasUsed,
+			StorageWrites: 1,
+			SlotsAccessed: 1,
+			SlotsCreated:  1,
+			StorageGrowth: 1,
+		},
+	}
+	if len(result.Contracts) != len(want) {
+		t.Fatalf("contract count mismatch: have %d, want %d", len(result.Contracts), len(want))
+	}
+	for _, have := range result.Contracts {
+		exp, ok := want[have.Address]
+		if !ok {
+			t.Errorf("unexpected contract %x", have.Address)
+			continue
+		}
+		exp.Address, exp.slots = have.Address, have.slots
+		if !reflect.DeepEqual(*have, exp) {
+			t.Errorf("contract %x stats mismatch: have %+v, want %+v", have.Address, *have, exp)
+		}
+	}
+	for i := 1; i < len(result.Contracts); i++ {
+		if result.Contracts[i-1].GasUsed < result.Contracts[i].GasUsed {
+			t.Errorf("contracts not ordered by gas used")
+		}
+	}
+	// Limited results must keep the contracts using the most gas
+	limit := 1
+	limited, err := api.ContractStats(context.Background(), 2, rpc.LatestBlockNumber, &ContractStatsConfig{Limit: &limit})
+	if err != nil {
+		t.Fatalf("failed to collect limited contract statistics: %v", err)
+	}
+	if len(limited.Contracts) != 1 || limited.Contracts[0].Address != result.Contracts[0].Address {
+		t.Errorf("limited result mismatch: have %d contracts", len(limited.Contracts))
+	}
+}
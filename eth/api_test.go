@@ -18,6 +18,7 @@ package eth
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"math/big"
 	"reflect"
@@ -26,9 +27,12 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 var dumper = spew.ConfigState{Indent: "    "}
@@ -201,3 +205,42 @@ func TestStorageRangeAt(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the block tags bounding token transfer queries are resolved through
+// the backend, and that unavailable blocks are rejected.
+func TestTokenTransferBounds(t *testing.T) {
+	pm, db := newTestProtocolManagerMust(t, downloader.FullSync, 4, nil, nil)
+	defer pm.Stop()
+
+	var (
+		ethash = pm.blockchain.Engine()
+		posa   = &testPoSA{Engine: ethash, safe: pm.blockchain.GetHeaderByNumber(2)}
+	)
+	tests := []struct {
+		engine consensus.Engine
+		number rpc.BlockNumber
+		fail   bool
+		want   uint64
+	}{
+		{engine: ethash, number: 3, want: 3},
+		{engine: ethash, number: rpc.LatestBlockNumber, want: 4},
+		{engine: ethash, number: rpc.PendingBlockNumber, want: 4},
+		{engine: ethash, number: rpc.SafeBlockNumber, fail: true},
+		{engine: posa, number: rpc.SafeBlockNumber, want: 2},
+		{engine: posa, number: rpc.FinalizedBlockNumber, fail: true},
+		{engine: posa, number: -5, fail: true},
+	}
+	for i, tt := range tests {
+		eth := &Ethereum{blockchain: pm.blockchain, engine: tt.engine, chainDb: db}
+		eth.APIBackend = &EthAPIBackend{eth: eth}
+
+		have, err := NewPublicEthereumAPI(eth).tokenTransferBound(context.Background(), tt.number)
+		if (err != nil) != tt.fail {
+			t.Errorf("test %d: error mismatch: have %v, want failure %v", i, err, tt.fail)
+			continue
+		}
+		if !tt.fail && have != tt.want {
+			t.Errorf("test %d: bound mismatch: have %d, want %d", i, have, tt.want)
+		}
+	}
+}
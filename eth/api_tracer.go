@@ -22,7 +22,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/ethereum/go-ethereum/consensus"
 	"io/ioutil"
 	"math/big"
 	"os"
@@ -32,9 +31,11 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/tracers"
@@ -75,6 +76,9 @@ type StdTraceConfig struct {
 type txTraceResult struct {
 	Result interface{} `json:"result,omitempty"` // Trace results produced by the tracer
 	Error  string      `json:"error,omitempty"`  // Trace failure produced by the tracer
+
+	SystemTx       bool   `json:"systemTx,omitempty"`       // Whether the transaction was issued by the consensus engine
+	SystemContract string `json:"systemContract,omitempty"` // Name of the system contract called by the transaction
 }
 
 // blockTraceTask represents a single block trace task when an entire chain is
@@ -210,15 +214,17 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 					msg, _ := tx.AsMessage(signer)
 					vmctx := core.NewEVMContext(msg, task.block.Header(), api.eth.blockchain, nil)
 
+					system, contract := api.systemTxInfo(tx, task.block.Header())
+
 					res, err := api.traceTx(ctx, msg, vmctx, task.statedb, config)
 					if err != nil {
-						task.results[i] = &txTraceResult{Error: err.Error()}
+						task.results[i] = &txTraceResult{Error: err.Error(), SystemTx: system, SystemContract: contract}
 						log.Warn("Tracing failed", "hash", tx.Hash(), "block", task.block.NumberU64(), "err", err)
 						break
 					}
 					// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
 					task.statedb.Finalise(api.eth.blockchain.Config().IsEIP158(task.block.Number()))
-					task.results[i] = &txTraceResult{Result: res}
+					task.results[i] = &txTraceResult{Result: res, SystemTx: system, SystemContract: contract}
 				}
 				// Stream the result back to the user or abort on teardown
 				select {
@@ -291,7 +297,7 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 				traced += uint64(len(txs))
 			}
 			// Generate the next state snapshot fast without tracing
-			_, _, _, err := api.eth.blockchain.Processor().Process(block, statedb, vm.Config{})
+			_, _, _, err := api.eth.blockchain.Processor().Process(block, statedb, vm.Config{}, nil)
 			if err != nil {
 				failed = err
 				break
@@ -356,19 +362,13 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 // EVM and returns them as a JSON object.
 func (api *PrivateDebugAPI) TraceBlockByNumber(ctx context.Context, number rpc.BlockNumber, config *TraceConfig) ([]*txTraceResult, error) {
 	// Fetch the block that we want to trace
-	var block *types.Block
-
-	switch number {
-	case rpc.PendingBlockNumber:
-		block = api.eth.miner.PendingBlock()
-	case rpc.LatestBlockNumber:
-		block = api.eth.blockchain.CurrentBlock()
-	default:
-		block = api.eth.blockchain.GetBlockByNumber(uint64(number))
+	block, err := api.eth.APIBackend.BlockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
 	}
 	// Trace the block if it was found
 	if block == nil {
-		return nil, fmt.Errorf("block #%d not found", number)
+		return nil, fmt.Errorf("block %v not found", number)
 	}
 	return api.traceBlock(ctx, block, config)
 }
@@ -483,13 +483,14 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 			for task := range jobs {
 				msg, _ := txs[task.index].AsMessage(signer)
 				vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain, nil)
+				system, contract := api.systemTxInfo(txs[task.index], block.Header())
 
 				res, err := api.traceTx(ctx, msg, vmctx, task.statedb, config)
 				if err != nil {
-					results[task.index] = &txTraceResult{Error: err.Error()}
+					results[task.index] = &txTraceResult{Error: err.Error(), SystemTx: system, SystemContract: contract}
 					continue
 				}
-				results[task.index] = &txTraceResult{Result: res}
+				results[task.index] = &txTraceResult{Result: res, SystemTx: system, SystemContract: contract}
 			}
 		}()
 	}
@@ -502,14 +503,8 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 		// Generate the next state snapshot fast without tracing
 		msg, _ := tx.AsMessage(signer)
 		vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain, nil)
-		if posa, ok := api.eth.engine.(consensus.PoSA); ok {
-			if isSystem, _ := posa.IsSystemTransaction(tx, block.Header()); isSystem {
-				balance := statedb.GetBalance(consensus.SystemAddress)
-				if balance.Cmp(common.Big0) > 0 {
-					statedb.SetBalance(consensus.SystemAddress, big.NewInt(0))
-					statedb.AddBalance(block.Header().Coinbase, balance)
-				}
-			}
+		if system, _ := api.systemTxInfo(tx, block.Header()); system {
+			collectSystemBalance(statedb, block.Coinbase())
 		}
 		vmenv := vm.NewEVM(vmctx, statedb, api.eth.blockchain.Config(), vm.Config{})
 		if _, _, _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas())); err != nil {
@@ -585,6 +580,10 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 			writer *bufio.Writer
 			err    error
 		)
+		// System transactions first move the collected fees to the coinbase
+		if system, _ := api.systemTxInfo(tx, block.Header()); system {
+			collectSystemBalance(statedb, block.Coinbase())
+		}
 		// If the transaction needs tracing, swap out the configs
 		if tx.Hash() == txHash || txHash == (common.Hash{}) {
 			// Generate a unique temporary file to dump it into
@@ -629,6 +628,30 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 	return dumps, nil
 }
 
+// systemTxInfo reports whether the transaction is a system transaction issued
+// by the consensus engine, and the name of the system contract it calls, if any.
+// Relayed cross-chain packages are not system transactions, but are labelled
+// with the system contract they are delivered to.
+func (api *PrivateDebugAPI) systemTxInfo(tx *types.Transaction, header *types.Header) (bool, string) {
+	posa, ok := api.eth.engine.(consensus.PoSA)
+	if !ok || tx.To() == nil {
+		return false, ""
+	}
+	system, _ := posa.IsSystemTransaction(tx, header)
+	return system, systemcontracts.Names[*tx.To()]
+}
+
+// collectSystemBalance moves the fees accumulated on the system address to the
+// coinbase, mirroring what the consensus engine does before applying a system
+// transaction.
+func collectSystemBalance(statedb *state.StateDB, coinbase common.Address) {
+	balance := statedb.GetBalance(consensus.SystemAddress)
+	if balance.Cmp(common.Big0) > 0 {
+		statedb.SetBalance(consensus.SystemAddress, big.NewInt(0))
+		statedb.AddBalance(coinbase, balance)
+	}
+}
+
 // containsTx reports whether the transaction with a certain hash
 // is contained within the specified block.
 func containsTx(block *types.Block, hash common.Hash) bool {
@@ -686,7 +709,7 @@ func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*
 		if block = api.eth.blockchain.GetBlockByNumber(block.NumberU64() + 1); block == nil {
 			return nil, fmt.Errorf("block #%d not found", block.NumberU64()+1)
 		}
-		_, _, _, err := api.eth.blockchain.Processor().Process(block, statedb, vm.Config{})
+		_, _, _, err := api.eth.blockchain.Processor().Process(block, statedb, vm.Config{}, nil)
 		if err != nil {
 			return nil, fmt.Errorf("processing block %d failed: %v", block.NumberU64(), err)
 		}
@@ -710,7 +733,9 @@ func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*
 }
 
 // TraceTransaction returns the structured logs created during the execution of EVM
-// and returns them as a JSON object.
+// and returns them as a JSON object. System transactions are replayed like the
+// consensus engine applies them, but the result is left as the tracer produced
+// it; the system transaction labels are only reported by the block tracers.
 func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (interface{}, error) {
 	// Retrieve the transaction and assemble its EVM context
 	tx, blockHash, _, index := rawdb.ReadTransaction(api.eth.ChainDb(), hash)
@@ -769,11 +794,7 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 	vmenv := vm.NewEVM(vmctx, statedb, api.eth.blockchain.Config(), vm.Config{Debug: true, Tracer: tracer})
 	if posa, ok := api.eth.engine.(consensus.PoSA); ok && message.From() == vmctx.Coinbase &&
 		posa.IsSystemContract(message.To()) && message.GasPrice().Cmp(big.NewInt(0)) == 0 {
-		balance := statedb.GetBalance(consensus.SystemAddress)
-		if balance.Cmp(common.Big0) > 0 {
-			statedb.SetBalance(consensus.SystemAddress, big.NewInt(0))
-			statedb.AddBalance(vmctx.Coinbase, balance)
-		}
+		collectSystemBalance(statedb, vmctx.Coinbase)
 	}
 	ret, gas, failed, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.Gas()))
 	if err != nil {
@@ -823,14 +844,8 @@ func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int, ree
 	for idx, tx := range block.Transactions() {
 		// Assemble the transaction call message and return if the requested offset
 		msg, _ := tx.AsMessage(signer)
-		if posa, ok := api.eth.engine.(consensus.PoSA); ok {
-			if isSystem, _ := posa.IsSystemTransaction(tx, block.Header()); isSystem {
-				balance := statedb.GetBalance(consensus.SystemAddress)
-				if balance.Cmp(common.Big0) > 0 {
-					statedb.SetBalance(consensus.SystemAddress, big.NewInt(0))
-					statedb.AddBalance(block.Header().Coinbase, balance)
-				}
-			}
+		if system, _ := api.systemTxInfo(tx, block.Header()); system {
+			collectSystemBalance(statedb, block.Coinbase())
 		}
 		context := core.NewEVMContext(msg, block.Header(), api.eth.blockchain, nil)
 		if idx == txIndex {
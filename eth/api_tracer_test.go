@@ -0,0 +1,202 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testPoSA wraps a regular engine, classifying transactions like parlia does:
+// free transactions from the block producer to a system contract are system
+// transactions. The safe and finalized blocks are fixed.
+type testPoSA struct {
+	consensus.Engine
+	safe, finalized *types.Header
+}
+
+func (p *testPoSA) IsSystemTransaction(tx *types.Transaction, header *types.Header) (bool, error) {
+	sender, err := types.Sender(types.MakeSigner(params.TestChainConfig, header.Number), tx)
+	if err != nil {
+		return false, err
+	}
+	return sender == header.Coinbase && p.IsSystemContract(tx.To()) && tx.GasPrice().Sign() == 0, nil
+}
+
+func (p *testPoSA) IsSystemContract(to *common.Address) bool {
+	return to != nil && systemcontracts.Names[*to] != ""
+}
+
+func (p *testPoSA) EnoughDistance(chain consensus.ChainReader, header *types.Header) bool {
+	return false
+}
+
+func (p *testPoSA) GetSafeHeader(chain consensus.ChainReader, header *types.Header) *types.Header {
+	return p.safe
+}
+
+func (p *testPoSA) GetFinalizedHeader(chain consensus.ChainReader, header *types.Header) *types.Header {
+	return p.finalized
+}
+
+// Tests that the fees on the system address are moved to the coinbase, and
+// that nothing is touched if there are none.
+func TestCollectSystemBalance(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	coinbase := common.Address{0xcb}
+
+	statedb.SetBalance(coinbase, big.NewInt(1))
+	collectSystemBalance(statedb, coinbase)
+	if statedb.Exist(consensus.SystemAddress) {
+		t.Errorf("empty system address created")
+	}
+	statedb.SetBalance(consensus.SystemAddress, big.NewInt(10))
+	collectSystemBalance(statedb, coinbase)
+	if balance := statedb.GetBalance(consensus.SystemAddress); balance.Sign() != 0 {
+		t.Errorf("system balance mismatch: have %v, want 0", balance)
+	}
+	if balance := statedb.GetBalance(coinbase); balance.Cmp(big.NewInt(11)) != 0 {
+		t.Errorf("coinbase balance mismatch: have %v, want 11", balance)
+	}
+}
+
+// Tests that the block tracers label system transactions and relayed packages,
+// and that all tracers credit the system fees to the producer before replaying
+// a system transaction. The system contract reverts unless the producer holds
+// more than it does without the fees.
+func TestTraceSystemTransactions(t *testing.T) {
+	var (
+		signer         = types.HomesteadSigner{}
+		producerKey, _ = crypto.GenerateKey()
+		producer       = crypto.PubkeyToAddress(producerKey.PublicKey)
+		validatorSet   = common.HexToAddress(systemcontracts.ValidatorContract)
+
+		// Reverts if the balance of the coinbase is below 1500
+		checkerCode = []byte{0x41, 0x31, 0x61, 0x05, 0xdc, 0x11, 0x60, 0x0a, 0x57, 0x00, 0x5b, 0x60, 0x00, 0x80, 0xfd}
+
+		db    = rawdb.NewMemoryDatabase()
+		gspec = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				testBank:     {Balance: big.NewInt(1000000)},
+				validatorSet: {Code: checkerCode, Balance: new(big.Int)},
+			},
+		}
+	)
+	genesis := gspec.MustCommit(db)
+	sign := func(tx *types.Transaction, key *ecdsa.PrivateKey) *types.Transaction {
+		signed, err := types.SignTx(tx, signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 2, func(i int, b *core.BlockGen) {
+		switch i {
+		case 0:
+			b.SetCoinbase(common.Address{0xcb})
+			b.AddTx(sign(types.NewTransaction(b.TxNonce(testBank), producer, big.NewInt(1000), params.TxGas, big.NewInt(1), nil), testBankKey))
+			b.AddTx(sign(types.NewTransaction(b.TxNonce(testBank), consensus.SystemAddress, big.NewInt(1000), params.TxGas, big.NewInt(1), nil), testBankKey))
+		case 1:
+			b.SetCoinbase(producer)
+			b.AddTx(sign(types.NewTransaction(b.TxNonce(testBank), validatorSet, new(big.Int), 100000, new(big.Int), nil), testBankKey))
+			b.AddTx(sign(types.NewTransaction(b.TxNonce(producer), validatorSet, new(big.Int), 100000, new(big.Int), nil), producerKey))
+		}
+	})
+	chain, err := core.NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	block := chain.GetBlockByNumber(2)
+	if receipts := chain.GetReceiptsByHash(block.Hash()); receipts[1].Status != types.ReceiptStatusFailed {
+		t.Fatalf("system transaction succeeded without collecting the fees")
+	}
+	eth := &Ethereum{blockchain: chain, engine: &testPoSA{Engine: chain.Engine()}, chainDb: db}
+	eth.APIBackend = &EthAPIBackend{eth: eth}
+	api := NewPrivateDebugAPI(eth)
+
+	// Block traces must label the transactions and replay the system one on the collected fees
+	results, err := api.TraceBlockByNumber(context.Background(), rpc.BlockNumber(2), nil)
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+	want := []struct {
+		system bool
+		name   string
+	}{
+		{false, "ValidatorSet"},
+		{true, "ValidatorSet"},
+	}
+	for i, tt := range want {
+		if results[i].SystemTx != tt.system || results[i].SystemContract != tt.name {
+			t.Errorf("tx %d: label mismatch: have (%v, %q), want (%v, %q)", i, results[i].SystemTx, results[i].SystemContract, tt.system, tt.name)
+		}
+	}
+	if res, ok := results[1].Result.(*ethapi.ExecutionResult); !ok || res.Failed {
+		t.Errorf("system transaction block trace failed")
+	}
+	// Transaction traces must replay the system transaction the same way
+	res, err := api.TraceTransaction(context.Background(), block.Transactions()[1].Hash(), nil)
+	if err != nil {
+		t.Fatalf("failed to trace system transaction: %v", err)
+	}
+	if res.(*ethapi.ExecutionResult).Failed {
+		t.Errorf("system transaction trace failed")
+	}
+	// Dumps must end the system transaction without an error
+	files, err := api.StandardTraceBlockToFile(context.Background(), block.Hash(), nil)
+	for _, file := range files {
+		defer os.Remove(file)
+	}
+	if err != nil {
+		t.Fatalf("failed to dump block trace: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("dump count mismatch: have %d, want 2", len(files))
+	}
+	dump, err := ioutil.ReadFile(files[1])
+	if err != nil {
+		t.Fatalf("failed to read system transaction dump: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(dump)), "\n")
+	if end := lines[len(lines)-1]; !strings.Contains(end, `"gasUsed"`) || strings.Contains(end, `"error"`) {
+		t.Errorf("system transaction dump end mismatch: %s", end)
+	}
+}
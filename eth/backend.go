@@ -18,6 +18,7 @@
 package eth
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -156,7 +157,14 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		bloomIndexer:      NewBloomIndexer(chainDb, params.BloomBitsBlocks, params.BloomConfirms),
 	}
 
-	eth.APIBackend = &EthAPIBackend{ctx.ExtRPCEnabled(), eth, nil}
+	var archive *rpc.Client
+	if config.ArchiveFallback != "" {
+		if archive, err = rpc.Dial(config.ArchiveFallback); err != nil {
+			return nil, fmt.Errorf("failed to connect to archive fallback: %v", err)
+		}
+		log.Info("Forwarding pruned state queries to archive node", "url", config.ArchiveFallback)
+	}
+	eth.APIBackend = &EthAPIBackend{ctx.ExtRPCEnabled(), eth, nil, archive}
 	ethAPI := ethapi.NewPublicBlockChainAPI(eth.APIBackend)
 	eth.engine = CreateConsensusEngine(ctx, chainConfig, &config.Ethash, config.Miner.Notify, config.Miner.Noverify, chainDb, ethAPI, genesisHash)
 
@@ -188,12 +196,23 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 			TrieDirtyDisabled:   config.NoPruning,
 			TrieTimeLimit:       config.TrieTimeout,
 			SnapshotLimit:       config.SnapshotCache,
+			TxLookupLimit:       config.TxLookupLimit,
+			TxLookupAddresses:   config.TxLookupAccounts,
+			TokenTransferIndex:  config.TokenTransferIndex,
 		}
 	)
 	eth.blockchain, err = core.NewBlockChain(chainDb, cacheConfig, chainConfig, eth.engine, vmConfig, eth.shouldPreserve)
 	if err != nil {
 		return nil, err
 	}
+	if config.LiveTracer != "" {
+		tracer, err := core.NewLiveTracer(config.LiveTracer, json.RawMessage(config.LiveTracerConfig))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create live tracer %q: %v", config.LiveTracer, err)
+		}
+		eth.blockchain.SetLiveTracer(tracer)
+		log.Info("Enabled live block tracing", "tracer", config.LiveTracer)
+	}
 	// Rewind the chain in case of an incompatible config upgrade.
 	if compat, ok := genesisErr.(*params.ConfigCompatError); ok {
 		log.Warn("Rewinding chain to upgrade configuration", "err", compat)
@@ -216,6 +235,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	if eth.protocolManager, err = NewProtocolManager(chainConfig, checkpoint, config.SyncMode, config.NetworkId, eth.eventMux, eth.txPool, eth.engine, eth.blockchain, chainDb, cacheLimit, config.Whitelist, config.DirectBroadcast); err != nil {
 		return nil, err
 	}
+	eth.protocolManager.SetTxPolicy(config.WithheldTxAddresses, config.WithheldTxDelay)
 
 	eth.miner = miner.New(eth, &config.Miner, chainConfig, eth.EventMux(), eth.engine, eth.isLocalBlock)
 	eth.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
@@ -303,6 +323,8 @@ func (s *Ethereum) APIs() []rpc.API {
 		apis = append(apis, s.lesServer.APIs()...)
 	}
 
+	filterAPI := filters.NewPublicFilterAPI(s.APIBackend, false, s.config.RangeLimit)
+
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -328,7 +350,12 @@ func (s *Ethereum) APIs() []rpc.API {
 		}, {
 			Namespace: "eth",
 			Version:   "1.0",
-			Service:   filters.NewPublicFilterAPI(s.APIBackend, false, s.config.RangeLimit),
+			Service:   filterAPI,
+			Public:    true,
+		}, {
+			Namespace: "system",
+			Version:   "1.0",
+			Service:   filters.NewPublicSystemContractAPI(filterAPI),
 			Public:    true,
 		}, {
 			Namespace: "admin",
@@ -588,6 +615,9 @@ func (s *Ethereum) Stop() error {
 	s.miner.Stop()
 	s.blockchain.Stop()
 	s.engine.Close()
+	if s.APIBackend.archive != nil {
+		s.APIBackend.archive.Close()
+	}
 	s.chainDb.Close()
 	s.eventMux.Stop()
 	return nil
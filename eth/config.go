@@ -61,10 +61,15 @@ var DefaultConfig = Config{
 		DelayLeftOver: 50 * time.Millisecond,
 	},
 	TxPool: core.DefaultTxPoolConfig,
+	// The gas price oracle samples the last minute of 3 second blocks, so its
+	// suggestions follow a spike within a minute without reacting to a single
+	// congested block. No floor is set as validators differ in the minimum price
+	// they accept, while the ceiling caps the suggestions during spikes.
 	GPO: gasprice.Config{
 		Blocks:          20,
 		Percentile:      60,
 		OracleThreshold: 1000,
+		MaxPrice:        big.NewInt(500 * params.GWei),
 	},
 }
 
@@ -109,6 +114,18 @@ type Config struct {
 	DirectBroadcast bool
 	RangeLimit      bool
 
+	TxLookupLimit    uint64           `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved
+	TxLookupAccounts []common.Address `toml:",omitempty"` // Accounts whose transactions are indexed, all if empty
+
+	// TokenTransferIndex enables indexing the ERC-20/BEP-20 token transfers of
+	// executed blocks by sender and recipient.
+	TokenTransferIndex bool `toml:",omitempty"`
+
+	// Transactions sent from or to these addresses are withheld from peers for
+	// the given delay, or until inclusion if the delay is zero
+	WithheldTxAddresses []common.Address `toml:",omitempty"`
+	WithheldTxDelay     time.Duration    `toml:",omitempty"`
+
 	// Whitelist of required block number -> hash values to accept
 	Whitelist map[uint64]common.Hash `toml:"-"`
 
@@ -161,6 +178,15 @@ type Config struct {
 	// RPCGasCap is the global gas cap for eth-call variants.
 	RPCGasCap *big.Int `toml:",omitempty"`
 
+	// ArchiveFallback is the endpoint of an archive node to forward state queries
+	// to which can't be served because the local state was pruned.
+	ArchiveFallback string `toml:",omitempty"`
+
+	// LiveTracer is the name of the registered live tracer to notify of every
+	// imported block, configured by the JSON document in LiveTracerConfig.
+	LiveTracer       string `toml:",omitempty"`
+	LiveTracerConfig string `toml:",omitempty"`
+
 	// Checkpoint is a hardcoded checkpoint which can be nil.
 	Checkpoint *params.TrustedCheckpoint `toml:",omitempty"`
 
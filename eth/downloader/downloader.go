@@ -112,8 +112,9 @@ type Downloader struct {
 	stateBloom *trie.SyncBloom // Bloom filter for fast trie node existence checks
 
 	// Statistics
-	syncStatsChainOrigin uint64 // Origin block number where syncing started at
-	syncStatsChainHeight uint64 // Highest block number known when syncing started
+	syncStatsChainOrigin uint64    // Origin block number where syncing started at
+	syncStatsChainHeight uint64    // Highest block number known when syncing started
+	syncStatsChainStart  time.Time // Time the sync from the origin block started at
 	syncStatsState       stateSyncStats
 	syncStatsLock        sync.RWMutex // Lock protecting the sync stats fields
 
@@ -277,6 +278,63 @@ func (d *Downloader) Progress() ethereum.SyncProgress {
 	}
 }
 
+// SyncStatus is a detailed view of the synchronisation progress, including the
+// phase the sync is in and an estimate of the remaining work.
+type SyncStatus struct {
+	ethereum.SyncProgress
+
+	Mode    SyncMode // Synchronisation mode of the current sync cycle
+	Phase   string   // Phase of the sync: idle, blocks or state
+	Elapsed time.Duration
+
+	DuplicateStates     uint64 // Number of state entries downloaded twice
+	StateBytes          uint64 // Size of the state entries processed
+	StateBytesRemaining uint64 // Estimated size of the pending state entries
+
+	ETA time.Duration // Estimated time until the current phase completes, zero if unknown
+}
+
+// Status retrieves the detailed synchronisation progress. The remaining state
+// size and the ETA are rough estimates extrapolated from the progress made since
+// the sync started; as state entries are discovered while downloading, the state
+// ETA tends to grow before shrinking.
+func (d *Downloader) Status() SyncStatus {
+	status := SyncStatus{
+		SyncProgress: d.Progress(),
+		Mode:         d.mode,
+		Phase:        "idle",
+	}
+	if !d.Synchronising() {
+		return status
+	}
+	d.syncStatsLock.RLock()
+	defer d.syncStatsLock.RUnlock()
+
+	stats := d.syncStatsState
+	status.DuplicateStates = stats.duplicate
+	status.StateBytes = stats.bytes
+	if stats.processed > 0 {
+		status.StateBytesRemaining = stats.bytes / stats.processed * stats.pending
+	}
+	if !d.syncStatsChainStart.IsZero() {
+		status.Elapsed = time.Since(d.syncStatsChainStart)
+	}
+	// Fast sync spends the end of the cycle downloading the pivot state
+	if d.mode == FastSync && atomic.LoadInt32(&d.committed) == 0 && stats.pending > 0 {
+		status.Phase = "state"
+		if done := stats.processed - stats.initial; done > 0 && !stats.started.IsZero() {
+			status.ETA = time.Duration(float64(time.Since(stats.started)) / float64(done) * float64(stats.pending))
+		}
+		return status
+	}
+	status.Phase = "blocks"
+	progress := status.SyncProgress
+	if done := progress.CurrentBlock - progress.StartingBlock; progress.CurrentBlock > progress.StartingBlock && progress.HighestBlock > progress.CurrentBlock {
+		status.ETA = time.Duration(float64(status.Elapsed) / float64(done) * float64(progress.HighestBlock-progress.CurrentBlock))
+	}
+	return status
+}
+
 // Synchronising returns whether the downloader is currently retrieving blocks.
 func (d *Downloader) Synchronising() bool {
 	return atomic.LoadInt32(&d.synchronising) > 0
@@ -447,6 +505,7 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 	d.syncStatsLock.Lock()
 	if d.syncStatsChainHeight <= origin || d.syncStatsChainOrigin > origin {
 		d.syncStatsChainOrigin = origin
+		d.syncStatsChainStart = time.Now()
 	}
 	d.syncStatsChainHeight = height
 	d.syncStatsLock.Unlock()
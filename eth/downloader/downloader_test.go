@@ -1658,3 +1658,93 @@ func testCheckpointEnforcement(t *testing.T, protocol int, mode SyncMode) {
 		assertOwnChain(t, tester, chain.len())
 	}
 }
+
+// Tests that the detailed sync status reports the phase of the sync and
+// extrapolates the remaining state size and the time left from the progress.
+func TestSyncStatus(t *testing.T) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	// Import a few blocks, so the block phase has made some progress
+	chain := testChainBase.shorten(11)
+	blocks := make(types.Blocks, 0, 10)
+	for _, hash := range chain.chain[1:] {
+		blocks = append(blocks, chain.blockm[hash])
+	}
+	if _, err := tester.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import blocks: %v", err)
+	}
+	tests := []struct {
+		syncing   bool
+		mode      SyncMode
+		committed bool
+		stats     stateSyncStats
+		started   time.Duration // Age of the state sync run, zero if not started
+
+		phase     string
+		remaining uint64        // Expected remaining state bytes
+		eta       time.Duration // Expected lower bound of the ETA, zero if unknown
+	}{
+		// Nothing is estimated while idle
+		{syncing: false, mode: FullSync, phase: "idle"},
+		// Ten of thirty blocks imported in ten seconds leave twenty seconds
+		{syncing: true, mode: FullSync, phase: "blocks", eta: 20 * time.Second},
+		// Once the pivot is committed, fast sync imports blocks again
+		{syncing: true, mode: FastSync, committed: true, stats: stateSyncStats{pending: 10}, phase: "blocks", eta: 20 * time.Second},
+		// A hundred entries of this run in ten seconds leave thirty seconds for
+		// the three hundred pending ones, ten bytes each
+		{
+			syncing: true, mode: FastSync,
+			stats:   stateSyncStats{processed: 200, initial: 100, pending: 300, bytes: 2000},
+			started: 10 * time.Second,
+			phase:   "state", remaining: 3000, eta: 30 * time.Second,
+		},
+		// Without progress in this run the state ETA is unknown
+		{
+			syncing: true, mode: FastSync,
+			stats:   stateSyncStats{processed: 100, initial: 100, pending: 300, bytes: 1000},
+			started: 10 * time.Second,
+			phase:   "state", remaining: 3000,
+		},
+	}
+	d := tester.downloader
+	for i, tt := range tests {
+		d.syncStatsLock.Lock()
+		d.mode = tt.mode
+		d.syncStatsChainOrigin, d.syncStatsChainHeight = 0, 30
+		d.syncStatsChainStart = time.Now().Add(-10 * time.Second)
+		d.syncStatsState = tt.stats
+		if tt.started > 0 {
+			d.syncStatsState.started = time.Now().Add(-tt.started)
+		}
+		d.syncStatsLock.Unlock()
+
+		if tt.syncing {
+			atomic.StoreInt32(&d.synchronising, 1)
+		} else {
+			atomic.StoreInt32(&d.synchronising, 0)
+		}
+		if tt.committed {
+			atomic.StoreInt32(&d.committed, 1)
+		} else {
+			atomic.StoreInt32(&d.committed, 0)
+		}
+		status := d.Status()
+		if status.Phase != tt.phase {
+			t.Errorf("test %d: phase mismatch: have %s, want %s", i, status.Phase, tt.phase)
+		}
+		if status.StateBytesRemaining != tt.remaining {
+			t.Errorf("test %d: remaining state bytes mismatch: have %d, want %d", i, status.StateBytesRemaining, tt.remaining)
+		}
+		// The estimates are extrapolated from the wall clock, allow some slack
+		if tt.eta == 0 && status.ETA != 0 {
+			t.Errorf("test %d: ETA mismatch: have %v, want unknown", i, status.ETA)
+		}
+		if tt.eta != 0 && (status.ETA < tt.eta || status.ETA > tt.eta+time.Second) {
+			t.Errorf("test %d: ETA mismatch: have %v, want %v", i, status.ETA, tt.eta)
+		}
+	}
+	atomic.StoreInt32(&d.synchronising, 0)
+}
@@ -55,6 +55,10 @@ type stateSyncStats struct {
 	duplicate  uint64 // Number of state entries downloaded twice
 	unexpected uint64 // Number of non-requested state entries received
 	pending    uint64 // Number of still pending state entries
+	bytes      uint64 // Size of the state entries processed
+
+	started time.Time // Time the first state entries of this run were processed
+	initial uint64    // Number of state entries processed before this run
 }
 
 // syncState starts downloading state with the given root hash.
@@ -353,7 +357,7 @@ func (s *stateSync) commit(force bool) error {
 	if err := b.Write(); err != nil {
 		return fmt.Errorf("DB write error: %v", err)
 	}
-	s.updateStats(s.numUncommitted, 0, 0, time.Since(start))
+	s.updateStats(s.numUncommitted, s.bytesUncommitted, 0, 0, time.Since(start))
 	s.numUncommitted = 0
 	s.bytesUncommitted = 0
 	return nil
@@ -423,7 +427,7 @@ func (s *stateSync) process(req *stateReq) (int, error) {
 
 	defer func(start time.Time) {
 		if duplicate > 0 || unexpected > 0 {
-			s.updateStats(0, duplicate, unexpected, time.Since(start))
+			s.updateStats(0, 0, duplicate, unexpected, time.Since(start))
 		}
 	}(time.Now())
 
@@ -478,12 +482,17 @@ func (s *stateSync) processNodeData(blob []byte) (bool, common.Hash, error) {
 
 // updateStats bumps the various state sync progress counters and displays a log
 // message for the user to see.
-func (s *stateSync) updateStats(written, duplicate, unexpected int, duration time.Duration) {
+func (s *stateSync) updateStats(written, bytes, duplicate, unexpected int, duration time.Duration) {
 	s.d.syncStatsLock.Lock()
 	defer s.d.syncStatsLock.Unlock()
 
+	if s.d.syncStatsState.started.IsZero() && written > 0 {
+		s.d.syncStatsState.started = time.Now().Add(-duration)
+		s.d.syncStatsState.initial = s.d.syncStatsState.processed
+	}
 	s.d.syncStatsState.pending = uint64(s.sched.Pending())
 	s.d.syncStatsState.processed += uint64(written)
+	s.d.syncStatsState.bytes += uint64(bytes)
 	s.d.syncStatsState.duplicate += uint64(duplicate)
 	s.d.syncStatsState.unexpected += uint64(unexpected)
 
@@ -141,6 +141,20 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	}
 	head := header.Number.Uint64()
 
+	// Resolve the safe and finalized tags into the blocks they currently point to
+	for _, limit := range []*int64{&f.begin, &f.end} {
+		if *limit != rpc.SafeBlockNumber.Int64() && *limit != rpc.FinalizedBlockNumber.Int64() {
+			continue
+		}
+		header, err := f.backend.HeaderByNumber(ctx, rpc.BlockNumber(*limit))
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			return nil, errors.New("no safe or finalized block available")
+		}
+		*limit = header.Number.Int64()
+	}
 	if f.begin == -1 {
 		f.begin = int64(head)
 	}
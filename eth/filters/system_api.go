@@ -0,0 +1,133 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"context"
+	"fmt"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/parlia"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// PublicSystemContractAPI offers access to the decoded events of the system
+// contracts (validator set, slashing, token hub, cross chain and the rest).
+type PublicSystemContractAPI struct {
+	backend    Backend
+	events     *EventSystem
+	rangeLimit bool
+}
+
+// NewPublicSystemContractAPI returns a new PublicSystemContractAPI instance,
+// sharing the event system of the given filter API.
+func NewPublicSystemContractAPI(filterAPI *PublicFilterAPI) *PublicSystemContractAPI {
+	return &PublicSystemContractAPI{
+		backend:    filterAPI.backend,
+		events:     filterAPI.events,
+		rangeLimit: filterAPI.rangeLimit,
+	}
+}
+
+// SystemEventCriteria selects the system contract events to return. An empty
+// contract list matches all system contracts.
+type SystemEventCriteria struct {
+	FromBlock *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock   *rpc.BlockNumber `json:"toBlock"`
+	Contracts []common.Address `json:"contracts"`
+}
+
+// systemContracts validates the requested contracts, defaulting to all of them.
+func systemContracts(contracts []common.Address) ([]common.Address, error) {
+	if len(contracts) == 0 {
+		for addr := range systemcontracts.Names {
+			contracts = append(contracts, addr)
+		}
+		return contracts, nil
+	}
+	for _, addr := range contracts {
+		if _, ok := systemcontracts.Names[addr]; !ok {
+			return nil, fmt.Errorf("%s is not a system contract", addr.Hex())
+		}
+	}
+	return contracts, nil
+}
+
+// GetEvents returns the decoded system contract events matching the criteria.
+func (api *PublicSystemContractAPI) GetEvents(ctx context.Context, crit SystemEventCriteria) ([]*parlia.SystemEvent, error) {
+	contracts, err := systemContracts(crit.Contracts)
+	if err != nil {
+		return nil, err
+	}
+	begin, end := rpc.LatestBlockNumber.Int64(), rpc.LatestBlockNumber.Int64()
+	if crit.FromBlock != nil {
+		begin = crit.FromBlock.Int64()
+	}
+	if crit.ToBlock != nil {
+		end = crit.ToBlock.Int64()
+	}
+	logs, err := NewRangeFilter(api.backend, begin, end, contracts, nil, api.rangeLimit).Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*parlia.SystemEvent, 0, len(logs))
+	for _, log := range logs {
+		events = append(events, parlia.DecodeSystemEvent(log))
+	}
+	return events, nil
+}
+
+// Events creates a subscription that fires for every event emitted by the
+// given system contracts in newly imported blocks.
+func (api *PublicSystemContractAPI) Events(ctx context.Context, contracts []common.Address) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	contracts, err := systemContracts(contracts)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		rpcSub      = notifier.CreateSubscription()
+		matchedLogs = make(chan []*types.Log)
+	)
+	logsSub, err := api.events.SubscribeLogs(ethereum.FilterQuery{Addresses: contracts}, matchedLogs)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		for {
+			select {
+			case logs := <-matchedLogs:
+				for _, log := range logs {
+					notifier.Notify(rpcSub.ID, parlia.DecodeSystemEvent(log))
+				}
+			case <-rpcSub.Err(): // client send an unsubscribe request
+				logsSub.Unsubscribe()
+				return
+			case <-notifier.Closed(): // connection dropped
+				logsSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
@@ -18,6 +18,7 @@ package gasprice
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 	"sort"
 	"sync"
@@ -29,13 +30,19 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
-var maxPrice = big.NewInt(500 * params.GWei)
+// DefaultMaxPrice is the ceiling of the suggested gas price if none is configured.
+var DefaultMaxPrice = big.NewInt(500 * params.GWei)
+
+// maxFeeHistory is the maximum number of blocks a fee history can span.
+const maxFeeHistory = 1024
 
 type Config struct {
 	Blocks          int
 	Percentile      int
 	Default         *big.Int `toml:",omitempty"`
 	OracleThreshold int      `toml:",omitempty"`
+	MinPrice        *big.Int `toml:",omitempty"` // Floor of the suggested gas price (nil = none)
+	MaxPrice        *big.Int `toml:",omitempty"` // Ceiling of the suggested gas price (nil = DefaultMaxPrice)
 }
 
 // Oracle recommends gas prices based on the content of recent
@@ -48,6 +55,8 @@ type Oracle struct {
 	fetchLock sync.Mutex
 
 	defaultPrice      *big.Int
+	minPrice          *big.Int
+	maxPrice          *big.Int
 	sampleTxThreshold int
 
 	checkBlocks, maxEmpty, maxBlocks int
@@ -67,10 +76,16 @@ func NewOracle(backend ethapi.Backend, params Config) *Oracle {
 	if percent > 100 {
 		percent = 100
 	}
+	maxPrice := params.MaxPrice
+	if maxPrice == nil || maxPrice.Sign() <= 0 {
+		maxPrice = DefaultMaxPrice
+	}
 	return &Oracle{
 		backend:           backend,
 		lastPrice:         params.Default,
 		defaultPrice:      params.Default,
+		minPrice:          params.MinPrice,
+		maxPrice:          maxPrice,
 		checkBlocks:       blocks,
 		maxEmpty:          blocks / 2,
 		maxBlocks:         blocks * 5,
@@ -146,17 +161,168 @@ func (gpo *Oracle) SuggestPrice(ctx context.Context) (*big.Int, error) {
 	} else {
 		price = gpo.defaultPrice
 	}
-	if price.Cmp(maxPrice) > 0 {
-		price = new(big.Int).Set(maxPrice)
-	}
-
 	gpo.cacheLock.Lock()
+	if gpo.minPrice != nil && price.Cmp(gpo.minPrice) < 0 {
+		price = new(big.Int).Set(gpo.minPrice)
+	}
+	if price.Cmp(gpo.maxPrice) > 0 {
+		price = new(big.Int).Set(gpo.maxPrice)
+	}
 	gpo.lastHead = headHash
 	gpo.lastPrice = price
 	gpo.cacheLock.Unlock()
 	return price, nil
 }
 
+// PriceBounds returns the floor and the ceiling of the suggested gas price. The
+// floor is nil if there is none.
+func (gpo *Oracle) PriceBounds() (*big.Int, *big.Int) {
+	gpo.cacheLock.RLock()
+	defer gpo.cacheLock.RUnlock()
+
+	return gpo.minPrice, gpo.maxPrice
+}
+
+// SetPriceBounds updates the floor and the ceiling of the suggested gas price,
+// invalidating the cached suggestion. A nil floor removes it, a nil ceiling
+// resets it to the default.
+func (gpo *Oracle) SetPriceBounds(min, max *big.Int) error {
+	if max == nil {
+		max = DefaultMaxPrice
+	}
+	if min != nil && min.Cmp(max) > 0 {
+		return fmt.Errorf("gas price floor %v above ceiling %v", min, max)
+	}
+	gpo.cacheLock.Lock()
+	defer gpo.cacheLock.Unlock()
+
+	gpo.minPrice, gpo.maxPrice = min, max
+	gpo.lastHead = common.Hash{}
+	return nil
+}
+
+// FeeHistory returns, for the given number of blocks up to and including the
+// last block, the ratio of the gas limit used and the gas prices paid at the
+// given percentiles of the gas used by the transactions of each block. System
+// transactions sent by the block producer are ignored. The last block is either
+// a number, or latest or pending, which both end the range at the head block.
+//
+// There is no base fee on this chain, so it is reported as zero for each block
+// and the block following the range, to keep the shape of the eth_feeHistory
+// response that wallets expect.
+func (gpo *Oracle) FeeHistory(ctx context.Context, blocks int, lastBlock rpc.BlockNumber, percentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error) {
+	for i, p := range percentiles {
+		if p < 0 || p > 100 || (i > 0 && p < percentiles[i-1]) {
+			return common.Big0, nil, nil, nil, fmt.Errorf("invalid reward percentile #%d: %f", i, p)
+		}
+	}
+	if blocks < 1 {
+		return common.Big0, nil, nil, nil, nil
+	}
+	if blocks > maxFeeHistory {
+		blocks = maxFeeHistory
+	}
+	head, err := gpo.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return common.Big0, nil, nil, nil, err
+	}
+	last := head.Number.Uint64()
+	switch {
+	case lastBlock == rpc.LatestBlockNumber || lastBlock == rpc.PendingBlockNumber:
+		// The pending block has no fees paid yet, end the range at the head
+	case lastBlock < 0:
+		tag, _ := lastBlock.MarshalText()
+		return common.Big0, nil, nil, nil, fmt.Errorf("unsupported block tag %q", tag)
+	case uint64(lastBlock) > last:
+		return common.Big0, nil, nil, nil, fmt.Errorf("request beyond head block: requested %d, head %d", lastBlock, last)
+	default:
+		last = uint64(lastBlock)
+	}
+	if uint64(blocks) > last+1 {
+		blocks = int(last + 1)
+	}
+	oldest := last + 1 - uint64(blocks)
+
+	var (
+		reward       = make([][]*big.Int, blocks)
+		baseFee      = make([]*big.Int, blocks+1)
+		gasUsedRatio = make([]float64, blocks)
+	)
+	for i := range baseFee {
+		baseFee[i] = new(big.Int)
+	}
+	for i := 0; i < blocks; i++ {
+		number := oldest + uint64(i)
+		block, err := gpo.backend.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if block == nil {
+			if err == nil {
+				err = fmt.Errorf("block #%d not found", number)
+			}
+			return common.Big0, nil, nil, nil, err
+		}
+		if block.GasLimit() > 0 {
+			gasUsedRatio[i] = float64(block.GasUsed()) / float64(block.GasLimit())
+		}
+		if len(percentiles) == 0 {
+			continue
+		}
+		receipts, err := gpo.backend.GetReceipts(ctx, block.Hash())
+		if err != nil {
+			return common.Big0, nil, nil, nil, err
+		}
+		reward[i] = blockRewards(types.MakeSigner(gpo.backend.ChainConfig(), block.Number()), block, receipts, percentiles)
+	}
+	if len(percentiles) == 0 {
+		reward = nil
+	}
+	return new(big.Int).SetUint64(oldest), reward, baseFee, gasUsedRatio, nil
+}
+
+// blockRewards returns the gas prices paid at the given percentiles of the gas
+// used by the non-system transactions of the block.
+func blockRewards(signer types.Signer, block *types.Block, receipts types.Receipts, percentiles []float64) []*big.Int {
+	type sample struct {
+		gasUsed  uint64
+		gasPrice *big.Int
+	}
+	var (
+		samples []sample
+		total   uint64
+	)
+	for i, tx := range block.Transactions() {
+		if i >= len(receipts) {
+			break
+		}
+		if sender, err := types.Sender(signer, tx); err != nil || sender == block.Coinbase() {
+			continue
+		}
+		samples = append(samples, sample{receipts[i].GasUsed, tx.GasPrice()})
+		total += receipts[i].GasUsed
+	}
+	rewards := make([]*big.Int, len(percentiles))
+	if len(samples) == 0 {
+		for i := range rewards {
+			rewards[i] = new(big.Int)
+		}
+		return rewards
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].gasPrice.Cmp(samples[j].gasPrice) < 0 })
+
+	var (
+		index   int
+		sumUsed = samples[0].gasUsed
+	)
+	for i, p := range percentiles {
+		threshold := uint64(float64(total) * p / 100)
+		for sumUsed < threshold && index < len(samples)-1 {
+			index++
+			sumUsed += samples[index].gasUsed
+		}
+		rewards[i] = new(big.Int).Set(samples[index].gasPrice)
+	}
+	return rewards
+}
+
 type getBlockPricesResult struct {
 	number int
 	price  *big.Int
@@ -0,0 +1,213 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testHead is the number of the head block of the test chain.
+const testHead = 10
+
+// testBackend serves the blocks of a generated chain to the oracle. Methods the
+// oracle does not need panic through the embedded nil interface.
+type testBackend struct {
+	ethapi.Backend
+	chain *core.BlockChain
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if number < 0 {
+		return b.chain.CurrentHeader(), nil
+	}
+	return b.chain.GetHeaderByNumber(uint64(number)), nil
+}
+
+func (b *testBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	if number < 0 {
+		return b.chain.CurrentBlock(), nil
+	}
+	return b.chain.GetBlockByNumber(uint64(number)), nil
+}
+
+func (b *testBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	return b.chain.GetReceiptsByHash(hash), nil
+}
+
+func (b *testBackend) ChainConfig() *params.ChainConfig {
+	return b.chain.Config()
+}
+
+// newTestBackend creates a chain in which block i holds a transfer paying a gas
+// price of i gwei. The producer of the head block additionally includes a free
+// system transaction of its own.
+func newTestBackend(t *testing.T) *testBackend {
+	var (
+		key, _       = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr         = crypto.PubkeyToAddress(key.PublicKey)
+		validator, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+		gspec        = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				addr: {Balance: big.NewInt(params.Ether)},
+				crypto.PubkeyToAddress(validator.PublicKey): {Balance: big.NewInt(params.Ether)},
+			},
+		}
+		signer = types.NewEIP155Signer(gspec.Config.ChainID)
+		db     = rawdb.NewMemoryDatabase()
+	)
+	genesis := gspec.MustCommit(db)
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, testHead, func(i int, b *core.BlockGen) {
+		if i == testHead-1 {
+			coinbase := crypto.PubkeyToAddress(validator.PublicKey)
+			b.SetCoinbase(coinbase)
+
+			tx, err := types.SignTx(types.NewTransaction(b.TxNonce(coinbase), common.Address{2}, common.Big1, params.TxGas, common.Big0, nil), signer, validator)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b.AddTx(tx)
+		}
+		price := new(big.Int).Mul(big.NewInt(int64(i+1)), big.NewInt(params.GWei))
+		tx, err := types.SignTx(types.NewTransaction(b.TxNonce(addr), common.Address{1}, common.Big1, params.TxGas, price, nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.AddTx(tx)
+	})
+	chain, err := core.NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	return &testBackend{chain: chain}
+}
+
+func gwei(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(params.GWei))
+}
+
+// Tests that the suggested gas price is clamped to the configured bounds, and
+// that updating them invalidates the cached suggestion.
+func TestSuggestPriceBounds(t *testing.T) {
+	backend := newTestBackend(t)
+	defer backend.chain.Stop()
+
+	oracle := NewOracle(backend, Config{Blocks: testHead, Percentile: 60, Default: gwei(1)})
+
+	tests := []struct {
+		min, max *big.Int
+		fail     bool
+		want     *big.Int
+	}{
+		{nil, nil, false, gwei(6)},         // Sixth lowest price of the ten blocks, the system transaction ignored
+		{gwei(7), nil, false, gwei(7)},     // Raised to the floor
+		{nil, gwei(5), false, gwei(5)},     // Lowered to the ceiling
+		{gwei(2), gwei(9), false, gwei(6)}, // Within the bounds
+		{gwei(9), gwei(2), true, gwei(6)},  // Rejected, previous bounds kept
+	}
+	for i, tt := range tests {
+		if err := oracle.SetPriceBounds(tt.min, tt.max); (err != nil) != tt.fail {
+			t.Errorf("test %d: bounds update error mismatch: have %v, want failure %v", i, err, tt.fail)
+		}
+		price, err := oracle.SuggestPrice(context.Background())
+		if err != nil {
+			t.Fatalf("test %d: failed to suggest price: %v", i, err)
+		}
+		if price.Cmp(tt.want) != 0 {
+			t.Errorf("test %d: price mismatch: have %v, want %v", i, price, tt.want)
+		}
+	}
+	if err := oracle.SetPriceBounds(nil, nil); err != nil {
+		t.Fatalf("failed to reset bounds: %v", err)
+	}
+	if min, max := oracle.PriceBounds(); min != nil || max.Cmp(DefaultMaxPrice) != 0 {
+		t.Errorf("reset bounds mismatch: have [%v, %v], want [<nil>, %v]", min, max, DefaultMaxPrice)
+	}
+}
+
+// Tests the validation and range clamping of fee history requests and the
+// rewards reported for each block.
+func TestFeeHistory(t *testing.T) {
+	backend := newTestBackend(t)
+	defer backend.chain.Stop()
+
+	oracle := NewOracle(backend, Config{Blocks: testHead, Percentile: 60})
+
+	tests := []struct {
+		blocks      int
+		last        rpc.BlockNumber
+		percentiles []float64
+
+		fail   bool
+		oldest uint64
+		count  int
+		reward []int64 // Reward of each block at the first percentile, in gwei
+	}{
+		{blocks: 3, last: 5, percentiles: []float64{50}, oldest: 3, count: 3, reward: []int64{3, 4, 5}},
+		{blocks: 2, last: rpc.LatestBlockNumber, percentiles: []float64{0, 100}, oldest: 9, count: 2, reward: []int64{9, 10}},
+		{blocks: 2, last: rpc.PendingBlockNumber, oldest: 9, count: 2},
+		{blocks: 100, last: 4, oldest: 0, count: 5},
+		{blocks: 0, last: rpc.LatestBlockNumber},
+		{blocks: 1, last: testHead + 1, fail: true},
+		{blocks: 1, last: rpc.SafeBlockNumber, fail: true},
+		{blocks: 1, last: rpc.FinalizedBlockNumber, fail: true},
+		{blocks: 1, last: rpc.LatestBlockNumber, percentiles: []float64{-1}, fail: true},
+		{blocks: 1, last: rpc.LatestBlockNumber, percentiles: []float64{101}, fail: true},
+		{blocks: 1, last: rpc.LatestBlockNumber, percentiles: []float64{50, 10}, fail: true},
+	}
+	for i, tt := range tests {
+		oldest, reward, baseFee, ratio, err := oracle.FeeHistory(context.Background(), tt.blocks, tt.last, tt.percentiles)
+		if (err != nil) != tt.fail {
+			t.Errorf("test %d: error mismatch: have %v, want failure %v", i, err, tt.fail)
+			continue
+		}
+		if tt.fail {
+			continue
+		}
+		if len(ratio) != tt.count || (tt.count > 0 && (oldest.Uint64() != tt.oldest || len(baseFee) != tt.count+1)) {
+			t.Errorf("test %d: range mismatch: have %d blocks from %v, want %d from %d", i, len(ratio), oldest, tt.count, tt.oldest)
+			continue
+		}
+		if len(tt.percentiles) == 0 {
+			if reward != nil {
+				t.Errorf("test %d: rewards reported without percentiles", i)
+			}
+			continue
+		}
+		for j, want := range tt.reward {
+			if len(reward[j]) != len(tt.percentiles) || reward[j][0].Cmp(gwei(want)) != 0 {
+				t.Errorf("test %d: block %d reward mismatch: have %v, want %d gwei", i, j, reward[j], want)
+			}
+		}
+	}
+}
@@ -24,6 +24,11 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		DiscoveryURLs           []string
 		NoPruning               bool
 		NoPrefetch              bool
+		TxLookupLimit           uint64                 `toml:",omitempty"`
+		TxLookupAccounts        []common.Address       `toml:",omitempty"`
+		TokenTransferIndex      bool                   `toml:",omitempty"`
+		WithheldTxAddresses     []common.Address       `toml:",omitempty"`
+		WithheldTxDelay         time.Duration          `toml:",omitempty"`
 		Whitelist               map[uint64]common.Hash `toml:"-"`
 		LightServ               int                    `toml:",omitempty"`
 		LightIngress            int                    `toml:",omitempty"`
@@ -48,6 +53,9 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		EWASMInterpreter        string
 		EVMInterpreter          string
 		RPCGasCap               *big.Int                       `toml:",omitempty"`
+		ArchiveFallback         string                         `toml:",omitempty"`
+		LiveTracer              string                         `toml:",omitempty"`
+		LiveTracerConfig        string                         `toml:",omitempty"`
 		Checkpoint              *params.TrustedCheckpoint      `toml:",omitempty"`
 		CheckpointOracle        *params.CheckpointOracleConfig `toml:",omitempty"`
 		OverrideIstanbul        *big.Int                       `toml:",omitempty"`
@@ -60,6 +68,11 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.DiscoveryURLs = c.DiscoveryURLs
 	enc.NoPruning = c.NoPruning
 	enc.NoPrefetch = c.NoPrefetch
+	enc.TxLookupLimit = c.TxLookupLimit
+	enc.TxLookupAccounts = c.TxLookupAccounts
+	enc.TokenTransferIndex = c.TokenTransferIndex
+	enc.WithheldTxAddresses = c.WithheldTxAddresses
+	enc.WithheldTxDelay = c.WithheldTxDelay
 	enc.Whitelist = c.Whitelist
 	enc.LightServ = c.LightServ
 	enc.LightIngress = c.LightIngress
@@ -84,6 +97,9 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.EWASMInterpreter = c.EWASMInterpreter
 	enc.EVMInterpreter = c.EVMInterpreter
 	enc.RPCGasCap = c.RPCGasCap
+	enc.ArchiveFallback = c.ArchiveFallback
+	enc.LiveTracer = c.LiveTracer
+	enc.LiveTracerConfig = c.LiveTracerConfig
 	enc.Checkpoint = c.Checkpoint
 	enc.CheckpointOracle = c.CheckpointOracle
 	enc.OverrideIstanbul = c.OverrideIstanbul
@@ -100,6 +116,11 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		DiscoveryURLs           []string
 		NoPruning               *bool
 		NoPrefetch              *bool
+		TxLookupLimit           *uint64                `toml:",omitempty"`
+		TxLookupAccounts        []common.Address       `toml:",omitempty"`
+		TokenTransferIndex      *bool                  `toml:",omitempty"`
+		WithheldTxAddresses     []common.Address       `toml:",omitempty"`
+		WithheldTxDelay         *time.Duration         `toml:",omitempty"`
 		Whitelist               map[uint64]common.Hash `toml:"-"`
 		LightServ               *int                   `toml:",omitempty"`
 		LightIngress            *int                   `toml:",omitempty"`
@@ -124,6 +145,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		EWASMInterpreter        *string
 		EVMInterpreter          *string
 		RPCGasCap               *big.Int                       `toml:",omitempty"`
+		ArchiveFallback         *string                        `toml:",omitempty"`
+		LiveTracer              *string                        `toml:",omitempty"`
+		LiveTracerConfig        *string                        `toml:",omitempty"`
 		Checkpoint              *params.TrustedCheckpoint      `toml:",omitempty"`
 		CheckpointOracle        *params.CheckpointOracleConfig `toml:",omitempty"`
 		OverrideIstanbul        *big.Int                       `toml:",omitempty"`
@@ -151,6 +175,21 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.NoPrefetch != nil {
 		c.NoPrefetch = *dec.NoPrefetch
 	}
+	if dec.TxLookupLimit != nil {
+		c.TxLookupLimit = *dec.TxLookupLimit
+	}
+	if dec.TxLookupAccounts != nil {
+		c.TxLookupAccounts = dec.TxLookupAccounts
+	}
+	if dec.TokenTransferIndex != nil {
+		c.TokenTransferIndex = *dec.TokenTransferIndex
+	}
+	if dec.WithheldTxAddresses != nil {
+		c.WithheldTxAddresses = dec.WithheldTxAddresses
+	}
+	if dec.WithheldTxDelay != nil {
+		c.WithheldTxDelay = *dec.WithheldTxDelay
+	}
 	if dec.Whitelist != nil {
 		c.Whitelist = dec.Whitelist
 	}
@@ -223,6 +262,15 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.RPCGasCap != nil {
 		c.RPCGasCap = dec.RPCGasCap
 	}
+	if dec.ArchiveFallback != nil {
+		c.ArchiveFallback = *dec.ArchiveFallback
+	}
+	if dec.LiveTracer != nil {
+		c.LiveTracer = *dec.LiveTracer
+	}
+	if dec.LiveTracerConfig != nil {
+		c.LiveTracerConfig = *dec.LiveTracerConfig
+	}
 	if dec.Checkpoint != nil {
 		c.Checkpoint = dec.Checkpoint
 	}
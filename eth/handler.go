@@ -85,6 +85,10 @@ type ProtocolManager struct {
 	txsSub        event.Subscription
 	minedBlockSub *event.TypeMuxSubscription
 
+	txPolicy    *txPolicy               // Propagation policy for private and withheld transactions
+	peerStats   *peerStatsTracker       // Propagation and health statistics of the peers
+	txReleaseCh chan types.Transactions // Channel for withheld transactions whose delay expired
+
 	whitelist map[uint64]common.Hash
 
 	// channels for fetcher, syncer, txsyncLoop
@@ -114,6 +118,9 @@ func NewProtocolManager(config *params.ChainConfig, checkpoint *params.TrustedCh
 		whitelist:       whitelist,
 		txsyncCh:        make(chan *txsync),
 		quitSync:        make(chan struct{}),
+		txPolicy:        newTxPolicy(nil, 0, types.NewEIP155Signer(blockchain.Config().ChainID)),
+		txReleaseCh:     make(chan types.Transactions),
+		peerStats:       newPeerStatsTracker(),
 	}
 
 	if mode == downloader.FullSync {
@@ -299,7 +306,10 @@ func (pm *ProtocolManager) runPeer(p *peer) error {
 	}
 	pm.peerWG.Add(1)
 	defer pm.peerWG.Done()
-	return pm.handle(p)
+
+	err := pm.handle(p)
+	pm.peerStats.disconnected(p.id, err)
+	return err
 }
 
 // handle is the callback invoked to manage the life cycle of an eth peer. When
@@ -330,6 +340,7 @@ func (pm *ProtocolManager) handle(p *peer) error {
 		return err
 	}
 	defer pm.removePeer(p.id)
+	pm.peerStats.connected(p)
 
 	// Register the peer in the downloader. If the downloader considers it banned, we disconnect
 	if err := pm.downloader.RegisterPeer(p.id, p.version, p); err != nil {
@@ -685,9 +696,11 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		// Schedule all the unknown hashes for retrieval
 		unknown := make(newBlockHashesData, 0, len(announces))
 		for _, block := range announces {
-			if !pm.blockchain.HasBlock(block.Hash, block.Number) {
+			known := pm.blockchain.HasBlock(block.Hash, block.Number)
+			if !known {
 				unknown = append(unknown, block)
 			}
+			pm.peerStats.block(p.id, block.Hash, known, msg.ReceivedAt)
 		}
 		for _, block := range unknown {
 			pm.blockFetcher.Notify(p.id, block.Hash, block.Number, time.Now(), p.RequestOneHeader, p.RequestBodies)
@@ -715,6 +728,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 
 		// Mark the peer as owning the block and schedule it for import
 		p.MarkBlock(request.Block.Hash())
+		pm.peerStats.block(p.id, request.Block.Hash(), pm.blockchain.HasBlock(request.Block.Hash(), request.Block.NumberU64()), msg.ReceivedAt)
 		pm.blockFetcher.Enqueue(p.id, request.Block)
 
 		// Assuming the block is importable by the peer, but possibly not yet done so,
@@ -740,9 +754,14 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
 		// Schedule all the unknown hashes for retrieval
+		known := 0
 		for _, hash := range hashes {
 			p.MarkTransaction(hash)
+			if pm.txpool.Has(hash) {
+				known++
+			}
 		}
+		pm.peerStats.transactions(p.id, len(hashes), known)
 		pm.txFetcher.Notify(p.id, hashes)
 
 	case msg.Code == GetPooledTransactionsMsg && p.version >= eth65:
@@ -765,9 +784,9 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 			} else if err != nil {
 				return errResp(ErrDecode, "msg %v: %v", msg, err)
 			}
-			// Retrieve the requested transaction, skipping if unknown to us
+			// Retrieve the requested transaction, skipping if unknown to us or withheld
 			tx := pm.txpool.Get(hash)
-			if tx == nil {
+			if tx == nil || pm.txPolicy.withheld(tx) {
 				continue
 			}
 			// If known, encode and queue for response packet
@@ -791,13 +810,18 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		if err := msg.Decode(&txs); err != nil {
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
+		known := 0
 		for i, tx := range txs {
 			// Validate and mark the remote transaction
 			if tx == nil {
 				return errResp(ErrDecode, "transaction %d is nil", i)
 			}
 			p.MarkTransaction(tx.Hash())
+			if pm.txpool.Has(tx.Hash()) {
+				known++
+			}
 		}
+		pm.peerStats.transactions(p.id, len(txs), known)
 		pm.txFetcher.Enqueue(p.id, txs, msg.Code == PooledTransactionsMsg)
 
 	default:
@@ -900,16 +924,36 @@ func (pm *ProtocolManager) minedBroadcastLoop() {
 func (pm *ProtocolManager) txBroadcastLoop() {
 	defer pm.wg.Done()
 
+	prune := time.NewTicker(time.Minute)
+	defer prune.Stop()
+
 	for {
 		select {
 		case event := <-pm.txsCh:
-			// For testing purpose only, disable propagation
-			if pm.broadcastTxAnnouncesOnly {
-				pm.BroadcastTransactions(event.Txs, false)
-				continue
+			txs, delayed := pm.txPolicy.filter(event.Txs)
+			if len(delayed) > 0 {
+				time.AfterFunc(pm.txPolicy.delay, func() {
+					select {
+					case pm.txReleaseCh <- delayed:
+					case <-pm.quitSync:
+					}
+				})
 			}
-			pm.BroadcastTransactions(event.Txs, true)  // First propagate transactions to peers
-			pm.BroadcastTransactions(event.Txs, false) // Only then announce to the rest
+			pm.broadcastTransactions(txs)
+
+		case delayed := <-pm.txReleaseCh:
+			// Withheld transactions are only propagated if still pending
+			var pooled types.Transactions
+			for _, tx := range delayed {
+				if pm.txpool.Has(tx.Hash()) {
+					pooled = append(pooled, tx)
+				}
+			}
+			txs, _ := pm.txPolicy.filter(pooled)
+			pm.broadcastTransactions(txs)
+
+		case <-prune.C:
+			pm.txPolicy.prune(pm.txpool.Has)
 
 		case <-pm.txsSub.Err():
 			return
@@ -917,6 +961,34 @@ func (pm *ProtocolManager) txBroadcastLoop() {
 	}
 }
 
+// broadcastTransactions propagates and announces the transactions to the
+// connected peers.
+func (pm *ProtocolManager) broadcastTransactions(txs types.Transactions) {
+	if len(txs) == 0 {
+		return
+	}
+	// For testing purpose only, disable propagation
+	if pm.broadcastTxAnnouncesOnly {
+		pm.BroadcastTransactions(txs, false)
+		return
+	}
+	pm.BroadcastTransactions(txs, true)  // First propagate transactions to peers
+	pm.BroadcastTransactions(txs, false) // Only then announce to the rest
+}
+
+// SetTxPolicy configures the transactions withheld from the network: the ones
+// sent from or to the given addresses are only propagated after the delay, or
+// not at all if the delay is zero.
+func (pm *ProtocolManager) SetTxPolicy(addresses []common.Address, delay time.Duration) {
+	pm.txPolicy = newTxPolicy(addresses, delay, types.NewEIP155Signer(pm.blockchain.Config().ChainID))
+}
+
+// MarkPrivateTx flags a transaction to be kept out of the network until it is
+// included in a block.
+func (pm *ProtocolManager) MarkPrivateTx(hash common.Hash) {
+	pm.txPolicy.markPrivate(hash)
+}
+
 // NodeInfo represents a short summary of the Ethereum sub-protocol metadata
 // known about the host peer.
 type NodeInfo struct {
@@ -0,0 +1,216 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/p2p"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	peerStatsLimit    = 1024 // Number of peers, connected or not, to keep statistics for
+	blockArrivalLimit = 1024 // Number of recent blocks to remember the first arrival of
+)
+
+var (
+	peerBlockLatencyTimer = metrics.NewRegisteredTimer("eth/peers/block/latency", nil)
+	peerBlockUselessMeter = metrics.NewRegisteredMeter("eth/peers/block/useless", nil)
+	peerTxUselessMeter    = metrics.NewRegisteredMeter("eth/peers/transaction/useless", nil)
+)
+
+// PeerStats is the health summary of a single peer, as tracked since it last
+// connected.
+type PeerStats struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	RemoteAddress    string    `json:"remoteAddress"`
+	Static           bool      `json:"static"`
+	Trusted          bool      `json:"trusted"`
+	Connected        bool      `json:"connected"`
+	ConnectedAt      time.Time `json:"connectedAt"`
+	DisconnectReason string    `json:"disconnectReason,omitempty"`
+
+	BlockAnnounces      uint64  `json:"blockAnnounces"`      // Block announcements and propagations received
+	BlocksFirst         uint64  `json:"blocksFirst"`         // Blocks this peer delivered before any other
+	BlockLatency        float64 `json:"blockLatency"`        // Mean delay behind the first delivery of a block (ms)
+	UselessBlocks       uint64  `json:"uselessBlocks"`       // Announced blocks which were already imported
+	Transactions        uint64  `json:"transactions"`        // Transactions announced or broadcast
+	UselessTransactions uint64  `json:"uselessTransactions"` // Transactions which were already pooled
+	UselessRatio        float64 `json:"uselessRatio"`        // Share of announced items which were useless
+
+	latency time.Duration // Total delay behind the first deliveries
+}
+
+// PeerStatsResult is the response of the admin_peerStats API.
+type PeerStatsResult struct {
+	Peers       []*PeerStats      `json:"peers"`
+	Disconnects map[string]uint64 `json:"disconnects"` // Number of disconnects per reason
+}
+
+// peerStatsTracker collects propagation and health statistics of the eth peers.
+type peerStatsTracker struct {
+	peers       *lru.Cache        // Statistics of recently seen peers by id
+	arrivals    *lru.Cache        // First arrival time of recent blocks by hash
+	disconnects map[string]uint64 // Number of disconnects per reason
+	lock        sync.Mutex
+}
+
+func newPeerStatsTracker() *peerStatsTracker {
+	peers, _ := lru.New(peerStatsLimit)
+	arrivals, _ := lru.New(blockArrivalLimit)
+	return &peerStatsTracker{
+		peers:       peers,
+		arrivals:    arrivals,
+		disconnects: make(map[string]uint64),
+	}
+}
+
+// connected starts tracking a freshly connected peer, resetting any previous
+// statistics of it.
+func (t *peerStatsTracker) connected(p *peer) {
+	info := p.Peer.Info()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.peers.Add(p.id, &PeerStats{
+		ID:            p.id,
+		Name:          p.Name(),
+		RemoteAddress: info.Network.RemoteAddress,
+		Static:        info.Network.Static,
+		Trusted:       info.Network.Trusted,
+		Connected:     true,
+		ConnectedAt:   time.Now(),
+	})
+}
+
+// disconnected records why the peer was dropped.
+func (t *peerStatsTracker) disconnected(id string, err error) {
+	reason := disconnectReason(err)
+	metrics.GetOrRegisterMeter("eth/peers/disconnect/"+strings.NewReplacer(" ", "_", "/", "").Replace(strings.ToLower(reason)), nil).Mark(1)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.disconnects[reason]++
+	if stats, ok := t.peers.Get(id); ok {
+		stats := stats.(*PeerStats)
+		stats.Connected = false
+		stats.DisconnectReason = reason
+	}
+}
+
+// block records the arrival of a block announcement or propagation from the
+// peer, known reporting whether the block was already imported.
+func (t *peerStatsTracker) block(id string, hash common.Hash, known bool, arrival time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	cached, ok := t.peers.Get(id)
+	if !ok {
+		return
+	}
+	stats := cached.(*PeerStats)
+	stats.BlockAnnounces++
+	if known {
+		stats.UselessBlocks++
+		peerBlockUselessMeter.Mark(1)
+	}
+	if first, ok := t.arrivals.Get(hash); ok {
+		delay := arrival.Sub(first.(time.Time))
+		if delay < 0 {
+			delay = 0
+		}
+		stats.latency += delay
+		peerBlockLatencyTimer.Update(delay)
+	} else {
+		t.arrivals.Add(hash, arrival)
+		stats.BlocksFirst++
+		peerBlockLatencyTimer.Update(0)
+	}
+}
+
+// transactions records a batch of transactions announced or broadcast by the
+// peer, useless of which were already pooled.
+func (t *peerStatsTracker) transactions(id string, total int, useless int) {
+	peerTxUselessMeter.Mark(int64(useless))
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if stats, ok := t.peers.Get(id); ok {
+		stats := stats.(*PeerStats)
+		stats.Transactions += uint64(total)
+		stats.UselessTransactions += uint64(useless)
+	}
+}
+
+// stats returns a snapshot of the statistics of all tracked peers, connected
+// ones first.
+func (t *peerStatsTracker) stats() *PeerStatsResult {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	result := &PeerStatsResult{
+		Peers:       make([]*PeerStats, 0, t.peers.Len()),
+		Disconnects: make(map[string]uint64, len(t.disconnects)),
+	}
+	for _, id := range t.peers.Keys() {
+		cached, ok := t.peers.Peek(id)
+		if !ok {
+			continue
+		}
+		stats := *cached.(*PeerStats)
+		if stats.BlockAnnounces > 0 {
+			stats.BlockLatency = float64(stats.latency) / float64(stats.BlockAnnounces) / float64(time.Millisecond)
+		}
+		if total := stats.BlockAnnounces + stats.Transactions; total > 0 {
+			stats.UselessRatio = float64(stats.UselessBlocks+stats.UselessTransactions) / float64(total)
+		}
+		result.Peers = append(result.Peers, &stats)
+	}
+	sort.SliceStable(result.Peers, func(i, j int) bool {
+		return result.Peers[i].Connected && !result.Peers[j].Connected
+	})
+	for reason, count := range t.disconnects {
+		result.Disconnects[reason] = count
+	}
+	return result
+}
+
+// disconnectReason reduces the error a peer was dropped with to its category,
+// dropping the details of protocol errors and the addresses of network ones.
+func disconnectReason(err error) string {
+	if err == nil {
+		return "unknown"
+	}
+	if reason, ok := err.(p2p.DiscReason); ok {
+		return reason.String()
+	}
+	reason := strings.SplitN(err.Error(), " - ", 2)[0]
+	if i := strings.LastIndex(reason, ": "); i >= 0 {
+		reason = reason[i+2:]
+	}
+	return reason
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// Tests that the peer statistics track block latencies, useless messages and
+// disconnect reasons.
+func TestPeerStatsTracker(t *testing.T) {
+	tracker := newPeerStatsTracker()
+
+	a := newPeer(65, p2p.NewPeer(enode.ID{1}, "a", nil), nil, nil)
+	b := newPeer(65, p2p.NewPeer(enode.ID{2}, "b", nil), nil, nil)
+	tracker.connected(a)
+	tracker.connected(b)
+
+	now := time.Now()
+	tracker.block(a.id, common.Hash{1}, false, now)
+	tracker.block(b.id, common.Hash{1}, false, now.Add(100*time.Millisecond))
+	tracker.block(b.id, common.Hash{2}, true, now)
+	tracker.transactions(a.id, 4, 1)
+
+	tracker.disconnected(b.id, p2p.DiscUselessPeer)
+	tracker.disconnected("unknown", errors.New("read tcp 127.0.0.1:30303->127.0.0.1:1234: i/o timeout"))
+
+	stats := tracker.stats()
+	if len(stats.Peers) != 2 {
+		t.Fatalf("tracked peer count mismatch: have %d, want 2", len(stats.Peers))
+	}
+	first, second := stats.Peers[0], stats.Peers[1]
+	if first.ID != a.id || !first.Connected {
+		t.Errorf("first peer mismatch: have %s (connected %v), want connected %s", first.ID, first.Connected, a.id)
+	}
+	if first.BlocksFirst != 1 || first.BlockLatency != 0 || first.UselessRatio != 0.2 {
+		t.Errorf("first peer stats mismatch: first %d, latency %v, useless %v", first.BlocksFirst, first.BlockLatency, first.UselessRatio)
+	}
+	if second.Connected || second.DisconnectReason != p2p.DiscUselessPeer.String() {
+		t.Errorf("second peer disconnect mismatch: connected %v, reason %q", second.Connected, second.DisconnectReason)
+	}
+	if second.BlocksFirst != 1 || second.UselessBlocks != 1 || second.BlockLatency != 50 {
+		t.Errorf("second peer stats mismatch: first %d, useless %d, latency %v", second.BlocksFirst, second.UselessBlocks, second.BlockLatency)
+	}
+	if stats.Disconnects[p2p.DiscUselessPeer.String()] != 1 || stats.Disconnects["i/o timeout"] != 1 {
+		t.Errorf("disconnect reasons mismatch: %v", stats.Disconnects)
+	}
+}
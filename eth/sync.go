@@ -56,6 +56,7 @@ func (pm *ProtocolManager) syncTransactions(p *peer) {
 	for _, batch := range pending {
 		txs = append(txs, batch...)
 	}
+	txs, _ = pm.txPolicy.filter(txs)
 	if len(txs) == 0 {
 		return
 	}
@@ -0,0 +1,155 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// txPolicy decides which transactions are withheld from the network. Private
+// transactions are never propagated, while transactions sent from or to one of
+// the configured addresses are withheld for the configured delay, or until they
+// get included if there is no delay.
+type txPolicy struct {
+	addresses map[common.Address]struct{} // Senders and recipients whose transactions are withheld
+	delay     time.Duration               // Time to withhold matching transactions, zero means until inclusion
+	signer    types.Signer                // Signer used to derive the transaction senders
+
+	private map[common.Hash]struct{}  // Transactions submitted privately
+	delayed map[common.Hash]time.Time // Release times of withheld transactions
+	lock    sync.RWMutex
+}
+
+// newTxPolicy creates a propagation policy withholding the transactions of the
+// given addresses.
+func newTxPolicy(addresses []common.Address, delay time.Duration, signer types.Signer) *txPolicy {
+	policy := &txPolicy{
+		addresses: make(map[common.Address]struct{}),
+		delay:     delay,
+		signer:    signer,
+		private:   make(map[common.Hash]struct{}),
+		delayed:   make(map[common.Hash]time.Time),
+	}
+	for _, addr := range addresses {
+		policy.addresses[addr] = struct{}{}
+	}
+	return policy
+}
+
+// markPrivate flags a transaction to never be propagated to peers.
+func (p *txPolicy) markPrivate(hash common.Hash) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.private[hash] = struct{}{}
+}
+
+// matches reports whether the transaction is sent from or to one of the
+// withheld addresses.
+func (p *txPolicy) matches(tx *types.Transaction) bool {
+	if len(p.addresses) == 0 {
+		return false
+	}
+	if to := tx.To(); to != nil {
+		if _, ok := p.addresses[*to]; ok {
+			return true
+		}
+	}
+	from, err := types.Sender(p.signer, tx)
+	if err != nil {
+		return false
+	}
+	_, ok := p.addresses[from]
+	return ok
+}
+
+// check reports whether the transaction may be propagated at the given time.
+// Withheld transactions subject to a delay are returned with their release
+// time, which is computed from now if they are not tracked yet. It does not
+// modify the policy, callers must hold at least the read lock.
+func (p *txPolicy) check(tx *types.Transaction, now time.Time) (bool, time.Time) {
+	hash := tx.Hash()
+	if _, ok := p.private[hash]; ok {
+		return false, time.Time{}
+	}
+	if !p.matches(tx) {
+		return true, time.Time{}
+	}
+	if p.delay == 0 {
+		return false, time.Time{}
+	}
+	release, ok := p.delayed[hash]
+	if !ok {
+		release = now.Add(p.delay)
+	}
+	return !now.Before(release), release
+}
+
+// filter splits the transactions into the ones that may be propagated right
+// away and the ones that need to be retried once their delay expires. Private
+// and indefinitely withheld transactions are in neither set. The delay of the
+// matching transactions starts when they are first filtered.
+func (p *txPolicy) filter(txs types.Transactions) (allowed types.Transactions, delayed types.Transactions) {
+	now := time.Now()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, tx := range txs {
+		ok, release := p.check(tx, now)
+		switch {
+		case ok:
+			allowed = append(allowed, tx)
+		case !release.IsZero():
+			if _, tracked := p.delayed[tx.Hash()]; !tracked {
+				p.delayed[tx.Hash()] = release
+			}
+			delayed = append(delayed, tx)
+		}
+	}
+	return allowed, delayed
+}
+
+// withheld reports whether the transaction may not be handed out to peers.
+func (p *txPolicy) withheld(tx *types.Transaction) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	ok, _ := p.check(tx, time.Now())
+	return !ok
+}
+
+// prune drops the tracking of transactions which are no longer in the pool.
+func (p *txPolicy) prune(has func(common.Hash) bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for hash := range p.private {
+		if !has(hash) {
+			delete(p.private, hash)
+		}
+	}
+	for hash := range p.delayed {
+		if !has(hash) {
+			delete(p.delayed, hash)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tests that the propagation policy withholds private transactions and the
+// transactions of the configured addresses.
+func TestTxPolicyFilter(t *testing.T) {
+	other, _ := crypto.GenerateKey()
+
+	var (
+		own     = newTestTransaction(testBankKey, 0, 0)
+		foreign = newTestTransaction(other, 0, 0)
+		private = newTestTransaction(other, 1, 0)
+	)
+	// Without delay, matching transactions are withheld until inclusion
+	policy := newTxPolicy([]common.Address{testBank}, 0, types.HomesteadSigner{})
+	policy.markPrivate(private.Hash())
+
+	allowed, delayed := policy.filter(types.Transactions{own, foreign, private})
+	if len(allowed) != 1 || allowed[0] != foreign {
+		t.Fatalf("allowed transactions mismatch: have %d, want 1", len(allowed))
+	}
+	if len(delayed) != 0 {
+		t.Fatalf("delayed transactions mismatch: have %d, want 0", len(delayed))
+	}
+	// With a delay, matching transactions are released once it expires. Querying
+	// a transaction must not start its delay.
+	policy = newTxPolicy([]common.Address{testBank}, 50*time.Millisecond, types.HomesteadSigner{})
+	if !policy.withheld(own) || len(policy.delayed) != 0 {
+		t.Fatalf("withheld query mismatch: withheld %v, tracked %d", policy.withheld(own), len(policy.delayed))
+	}
+	if allowed, delayed = policy.filter(types.Transactions{own}); len(allowed) != 0 || len(delayed) != 1 {
+		t.Fatalf("transaction not delayed: allowed %d, delayed %d", len(allowed), len(delayed))
+	}
+	time.Sleep(100 * time.Millisecond)
+	if policy.withheld(own) {
+		t.Fatalf("transaction withheld after delay")
+	}
+	// Pruning drops the tracking of transactions no longer pooled
+	policy.markPrivate(private.Hash())
+	policy.prune(func(common.Hash) bool { return false })
+	if len(policy.private) != 0 || len(policy.delayed) != 0 {
+		t.Fatalf("stale transactions not pruned: private %d, delayed %d", len(policy.private), len(policy.delayed))
+	}
+}
@@ -290,6 +290,16 @@ func (ec *Client) TransactionRecipientsInBlock(ctx context.Context, number *big.
 	return rs, err
 }
 
+// TransactionReceiptsByBlockHash returns the receipts of all transactions in the given block.
+func (ec *Client) TransactionReceiptsByBlockHash(ctx context.Context, blockHash common.Hash) ([]*types.Receipt, error) {
+	var rs []*types.Receipt
+	err := ec.c.CallContext(ctx, &rs, "eth_getTransactionReceipts", blockHash)
+	if err != nil {
+		return nil, err
+	}
+	return rs, err
+}
+
 // TransactionDataAndReceipt returns the original data and receipt of a transaction by transaction hash.
 // Note that the receipt is not available for pending transactions.
 func (ec *Client) TransactionDataAndReceipt(ctx context.Context, txHash common.Hash) (*types.OriginalDataAndReceipt, error) {
@@ -320,6 +330,16 @@ func toBlockNumArg(number *big.Int) string {
 	if number == nil {
 		return "latest"
 	}
+	if number.IsInt64() {
+		switch rpc.BlockNumber(number.Int64()) {
+		case rpc.PendingBlockNumber:
+			return "pending"
+		case rpc.SafeBlockNumber:
+			return "safe"
+		case rpc.FinalizedBlockNumber:
+			return "finalized"
+		}
+	}
 	return hexutil.EncodeBig(number)
 }
 
@@ -36,6 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -66,6 +67,42 @@ func (s *PublicEthereumAPI) GasPrice(ctx context.Context) (*hexutil.Big, error)
 	return (*hexutil.Big)(price), err
 }
 
+type feeHistoryResult struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+// FeeHistory returns the gas used ratio and the gas prices paid at the given
+// percentiles for a range of blocks. The base fee is always zero on this chain.
+func (s *PublicEthereumAPI) FeeHistory(ctx context.Context, blockCount hexutil.Uint, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*feeHistoryResult, error) {
+	oldest, reward, baseFee, gasUsed, err := s.b.FeeHistory(ctx, int(blockCount), lastBlock, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	results := &feeHistoryResult{
+		OldestBlock:  (*hexutil.Big)(oldest),
+		GasUsedRatio: gasUsed,
+	}
+	if reward != nil {
+		results.Reward = make([][]*hexutil.Big, len(reward))
+		for i, w := range reward {
+			results.Reward[i] = make([]*hexutil.Big, len(w))
+			for j, v := range w {
+				results.Reward[i][j] = (*hexutil.Big)(v)
+			}
+		}
+	}
+	if baseFee != nil {
+		results.BaseFee = make([]*hexutil.Big, len(baseFee))
+		for i, v := range baseFee {
+			results.BaseFee[i] = (*hexutil.Big)(v)
+		}
+	}
+	return results, nil
+}
+
 // ProtocolVersion returns the current Ethereum protocol version this node supports
 func (s *PublicEthereumAPI) ProtocolVersion() hexutil.Uint {
 	return hexutil.Uint(s.b.ProtocolVersion())
@@ -95,6 +132,27 @@ func (s *PublicEthereumAPI) Syncing() (interface{}, error) {
 	}, nil
 }
 
+// SyncProgress returns a detailed view of the synchronisation progress: the sync
+// mode and phase, the state download statistics and an estimate of the time
+// until the current phase completes. Sizes are in bytes, durations in seconds.
+func (s *PublicEthereumAPI) SyncProgress() map[string]interface{} {
+	status := s.b.Downloader().Status()
+	return map[string]interface{}{
+		"mode":                status.Mode.String(),
+		"phase":               status.Phase,
+		"startingBlock":       hexutil.Uint64(status.StartingBlock),
+		"currentBlock":        hexutil.Uint64(status.CurrentBlock),
+		"highestBlock":        hexutil.Uint64(status.HighestBlock),
+		"pulledStates":        hexutil.Uint64(status.PulledStates),
+		"knownStates":         hexutil.Uint64(status.KnownStates),
+		"duplicateStates":     hexutil.Uint64(status.DuplicateStates),
+		"stateBytes":          hexutil.Uint64(status.StateBytes),
+		"stateBytesRemaining": hexutil.Uint64(status.StateBytesRemaining),
+		"elapsed":             hexutil.Uint64(status.Elapsed / time.Second),
+		"eta":                 hexutil.Uint64(status.ETA / time.Second),
+	}
+}
+
 // PublicTxPoolAPI offers and API for the transaction pool. It only operates on data that is non confidential.
 type PublicTxPoolAPI struct {
 	b Backend
@@ -132,6 +190,65 @@ func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransac
 	return content
 }
 
+// AltContent returns the transactions contained within the separate alt lane of
+// the transaction pool, grouped by account and nonce.
+func (s *PublicTxPoolAPI) AltContent() map[string]map[string]*RPCTransaction {
+	content := make(map[string]map[string]*RPCTransaction)
+	for account, txs := range s.b.TxPoolAltContent() {
+		dump := make(map[string]*RPCTransaction)
+		for _, tx := range txs {
+			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+		}
+		content[account.Hex()] = dump
+	}
+	return content
+}
+
+// ContentFromArgs represents the optional filters of a txpool_contentFrom query.
+type ContentFromArgs struct {
+	MinGasPrice *hexutil.Big    `json:"minGasPrice"`
+	Offset      *hexutil.Uint64 `json:"offset"`
+	Limit       *hexutil.Uint64 `json:"limit"`
+}
+
+// ContentFrom returns the transactions contained within the transaction pool
+// sent by the given address. Transactions cheaper than the requested minimum
+// gas price are skipped, the rest are paginated in pending-then-queued nonce
+// order.
+func (s *PublicTxPoolAPI) ContentFrom(addr common.Address, args *ContentFromArgs) map[string]map[string]*RPCTransaction {
+	content := map[string]map[string]*RPCTransaction{
+		"pending": make(map[string]*RPCTransaction),
+		"queued":  make(map[string]*RPCTransaction),
+	}
+	if args == nil {
+		args = new(ContentFromArgs)
+	}
+	var (
+		pending, queue = s.b.TxPoolContentFrom(addr)
+		skipped, added uint64
+	)
+	collect := func(txs types.Transactions, dump map[string]*RPCTransaction) {
+		for _, tx := range txs {
+			if args.MinGasPrice != nil && tx.GasPrice().Cmp(args.MinGasPrice.ToInt()) < 0 {
+				continue
+			}
+			if args.Offset != nil && skipped < uint64(*args.Offset) {
+				skipped++
+				continue
+			}
+			if args.Limit != nil && added >= uint64(*args.Limit) {
+				return
+			}
+			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+			added++
+		}
+	}
+	collect(pending, content["pending"])
+	collect(queue, content["queued"])
+
+	return content
+}
+
 // Status returns the number of pending and queued transaction in the pool.
 func (s *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
 	pending, queue := s.b.Stats()
@@ -536,6 +653,113 @@ func (s *PublicBlockChainAPI) GetBalance(ctx context.Context, address common.Add
 	return (*hexutil.Big)(state.GetBalance(address)), state.Error()
 }
 
+// maxBalanceChangesRange is the maximum number of blocks GetBalanceChanges
+// is allowed to scan in a single call. It keeps the range, including the state
+// preceding it, within the recent states retained by a pruning node.
+const maxBalanceChangesRange = core.TriesInMemory - 1
+
+// transferEventTopic is the topic of the BEP20 Transfer(address,address,uint256) event.
+var transferEventTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// BalanceChange is a single change to the BNB or BEP20 balance of an account.
+type BalanceChange struct {
+	BlockNumber hexutil.Uint64  `json:"blockNumber"`
+	BlockHash   common.Hash     `json:"blockHash"`
+	TxHash      *common.Hash    `json:"transactionHash,omitempty"` // Only set for BEP20 transfers
+	Token       *common.Address `json:"token,omitempty"`           // Nil for BNB balance changes
+	Delta       *hexutil.Big    `json:"delta"`
+	Balance     *hexutil.Big    `json:"balance,omitempty"` // BNB balance after the block
+}
+
+// GetBalanceChanges returns the changes to the BNB balance of the address in the
+// given block range, one entry per block which changed it. If tokens is set, the
+// BEP20 transfers from or to the address are returned as well, as derived from
+// the Transfer events. Balances are read from the state of each block, which
+// is served from the snapshot if enabled, so the range is limited to the recent
+// blocks whose state is retained unless the node is an archive node.
+func (s *PublicBlockChainAPI) GetBalanceChanges(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber, tokens *bool) ([]*BalanceChange, error) {
+	from, err := s.b.HeaderByNumber(ctx, fromBlock)
+	if from == nil || err != nil {
+		return nil, fmt.Errorf("block %d not found", fromBlock)
+	}
+	to, err := s.b.HeaderByNumber(ctx, toBlock)
+	if to == nil || err != nil {
+		return nil, fmt.Errorf("block %d not found", toBlock)
+	}
+	begin, end := from.Number.Uint64(), to.Number.Uint64()
+	if begin > end {
+		return nil, fmt.Errorf("invalid block range %d-%d", begin, end)
+	}
+	if end-begin >= maxBalanceChangesRange {
+		return nil, fmt.Errorf("block range %d-%d exceeds the limit of %d blocks", begin, end, maxBalanceChangesRange)
+	}
+	// Retrieve the balance before the range to compare the first block against.
+	// States are pruned oldest first, so if it is available the rest are too.
+	prev := new(big.Int)
+	if begin > 0 {
+		statedb, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(begin-1))
+		if statedb == nil || err != nil {
+			return nil, fmt.Errorf("state of block %d is not available, balance changes can only be tracked over the retained recent state: %v", begin-1, err)
+		}
+		prev = statedb.GetBalance(address)
+	}
+	var (
+		changes []*BalanceChange
+		topic   = common.BytesToHash(address.Bytes())
+	)
+	for number := begin; number <= end; number++ {
+		statedb, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(number))
+		if statedb == nil || err != nil {
+			return nil, fmt.Errorf("state of block %d unavailable: %v", number, err)
+		}
+		if balance := statedb.GetBalance(address); balance.Cmp(prev) != 0 {
+			changes = append(changes, &BalanceChange{
+				BlockNumber: hexutil.Uint64(number),
+				BlockHash:   header.Hash(),
+				Delta:       (*hexutil.Big)(new(big.Int).Sub(balance, prev)),
+				Balance:     (*hexutil.Big)(balance),
+			})
+			prev = balance
+		}
+		// Skip the receipts unless the bloom filter indicates a matching transfer
+		if tokens == nil || !*tokens {
+			continue
+		}
+		if !types.BloomLookup(header.Bloom, transferEventTopic) || !types.BloomLookup(header.Bloom, topic) {
+			continue
+		}
+		receipts, err := s.b.GetReceipts(ctx, header.Hash())
+		if err != nil {
+			return nil, err
+		}
+		for _, receipt := range receipts {
+			for _, txLog := range receipt.Logs {
+				if len(txLog.Topics) != 3 || txLog.Topics[0] != transferEventTopic || len(txLog.Data) != 32 {
+					continue
+				}
+				value := new(big.Int).SetBytes(txLog.Data)
+				switch {
+				case txLog.Topics[1] == topic && txLog.Topics[2] == topic:
+					value = new(big.Int)
+				case txLog.Topics[1] == topic:
+					value.Neg(value)
+				case txLog.Topics[2] != topic:
+					continue
+				}
+				token, txHash := txLog.Address, receipt.TxHash
+				changes = append(changes, &BalanceChange{
+					BlockNumber: hexutil.Uint64(number),
+					BlockHash:   header.Hash(),
+					TxHash:      &txHash,
+					Token:       &token,
+					Delta:       (*hexutil.Big)(value),
+				})
+			}
+		}
+	}
+	return changes, nil
+}
+
 // Result structs for GetProof
 type AccountResult struct {
 	Address      common.Address  `json:"address"`
@@ -798,15 +1022,8 @@ type account struct {
 	StateDiff *map[common.Hash]common.Hash `json:"stateDiff"`
 }
 
-func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides map[common.Address]account, vmCfg vm.Config, timeout time.Duration, globalGasCap *big.Int) ([]byte, uint64, bool, error) {
-	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
-
-	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
-	if state == nil || err != nil {
-		return nil, 0, false, err
-	}
-
-	// Override the fields of specified contracts before execution.
+// applyStateOverrides overrides the fields of the specified accounts in the state.
+func applyStateOverrides(state *state.StateDB, overrides map[common.Address]account) error {
 	for addr, account := range overrides {
 		// Override account nonce.
 		if account.Nonce != nil {
@@ -821,7 +1038,7 @@ func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.Blo
 			state.SetBalance(addr, (*big.Int)(*account.Balance))
 		}
 		if account.State != nil && account.StateDiff != nil {
-			return nil, 0, false, fmt.Errorf("account %s has both 'state' and 'stateDiff'", addr.Hex())
+			return fmt.Errorf("account %s has both 'state' and 'stateDiff'", addr.Hex())
 		}
 		// Replace entire state if caller requires.
 		if account.State != nil {
@@ -834,6 +1051,20 @@ func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.Blo
 			}
 		}
 	}
+	return nil
+}
+
+func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides map[common.Address]account, vmCfg vm.Config, timeout time.Duration, globalGasCap *big.Int) ([]byte, uint64, bool, error) {
+	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
+
+	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, 0, false, err
+	}
+	// Override the fields of specified contracts before execution.
+	if err := applyStateOverrides(state, overrides); err != nil {
+		return nil, 0, false, err
+	}
 
 	// Setup context so it may be cancelled the call has completed
 	// or, in case of unmetered gas, setup a context with a timeout.
@@ -889,6 +1120,88 @@ func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNrOr
 	return (hexutil.Bytes)(result), err
 }
 
+// CallManyResult is the outcome of a single call of a bundle simulated by CallMany.
+type CallManyResult struct {
+	ReturnValue hexutil.Bytes  `json:"returnValue"`
+	GasUsed     hexutil.Uint64 `json:"gasUsed"`
+	Logs        []*types.Log   `json:"logs"`
+	Error       string         `json:"error,omitempty"`
+}
+
+// CallMany executes the given calls in order on top of the state of the given
+// block, each call observing the state changes of the ones before it. This
+// allows simulating a bundle of transactions, e.g. to check the outcome of a
+// swap preceded by its approval. A call that fails or reverts does not abort
+// the bundle, its error is reported in its result instead.
+//
+// Additionally, the caller can specify a batch of contract for fields overriding,
+// applied once before the first call.
+//
+// Note, this function doesn't make and changes in the state/blockchain.
+func (s *PublicBlockChainAPI) CallMany(ctx context.Context, calls []CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *map[common.Address]account) ([]*CallManyResult, error) {
+	defer func(start time.Time) { log.Debug("Executing EVM call bundle finished", "calls", len(calls), "runtime", time.Since(start)) }(time.Now())
+
+	statedb, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	if overrides != nil {
+		if err := applyStateOverrides(statedb, *overrides); err != nil {
+			return nil, err
+		}
+	}
+	// Bound the execution of the whole bundle, as for a single call
+	timeout := 5 * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	results := make([]*CallManyResult, 0, len(calls))
+	for i, args := range calls {
+		msg := args.ToMessage(s.b.RPCGasCap())
+		evm, vmError, err := s.b.GetEVM(ctx, msg, statedb, header)
+		if err != nil {
+			return nil, err
+		}
+		// Stop the EVM once the bundle is done or timed out
+		done := make(chan struct{})
+		go func() {
+			select {
+			case <-ctx.Done():
+				evm.Cancel()
+			case <-done:
+			}
+		}()
+		// Simulated calls have no hash, key their logs by their position instead
+		statedb.Prepare(common.BigToHash(big.NewInt(int64(i+1))), header.Hash(), i)
+
+		ret, gas, failed, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(math.MaxUint64))
+		close(done)
+		if err := vmError(); err != nil {
+			return nil, err
+		}
+		if evm.Cancelled() {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+		}
+		result := &CallManyResult{ReturnValue: ret, GasUsed: hexutil.Uint64(gas), Logs: []*types.Log{}}
+		switch {
+		case err != nil:
+			result.Error = err.Error()
+		case failed:
+			result.Error = "execution reverted"
+		default:
+			for _, txLog := range statedb.GetLogs(common.BigToHash(big.NewInt(int64(i + 1)))) {
+				txLog.TxHash = common.Hash{}
+				result.Logs = append(result.Logs, txLog)
+			}
+		}
+		results = append(results, result)
+
+		// Finalise the state so the next call observes the changes, as in a block
+		statedb.Finalise(s.b.ChainConfig().IsEIP158(header.Number))
+	}
+	return results, nil
+}
+
 func DoEstimateGas(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, gasCap *big.Int) (hexutil.Uint64, error) {
 	// Binary search the gas requirement, as it may be higher than the amount used
 	var (
@@ -1300,6 +1613,10 @@ func (s *PublicTransactionPoolAPI) GetTransactionCount(ctx context.Context, addr
 	// Resolve block number and use its state to ask for the nonce
 	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
 	if state == nil || err != nil {
+		var nonce *hexutil.Uint64
+		if forwarded, err := forwardToArchive(ctx, s.b, err, &nonce, "eth_getTransactionCount", address, blockNrOrHash); forwarded {
+			return nonce, err
+		}
 		return nil, err
 	}
 	nonce := state.GetNonce(address)
@@ -1342,17 +1659,34 @@ func (s *PublicTransactionPoolAPI) GetRawTransactionByHash(ctx context.Context,
 	return rlp.EncodeToBytes(tx)
 }
 
-// GetTransactionReceipt returns the transaction receipt for the given transaction hash.
+// GetTransactionReceiptsByBlockNumber returns all the transaction receipts of
+// the block with the given number or tag in a single call.
 func (s *PublicTransactionPoolAPI) GetTransactionReceiptsByBlockNumber(ctx context.Context, blockNr rpc.BlockNumber) ([]map[string]interface{}, error) {
-	blockNumber := uint64(blockNr.Int64())
-	blockHash := rawdb.ReadCanonicalHash(s.b.ChainDb(), blockNumber)
+	header, err := s.b.HeaderByNumber(ctx, blockNr)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	return s.blockReceipts(ctx, header.Hash(), header.Number.Uint64())
+}
+
+// GetTransactionReceipts returns all the transaction receipts of the block with
+// the given hash in a single call.
+func (s *PublicTransactionPoolAPI) GetTransactionReceipts(ctx context.Context, blockHash common.Hash) ([]map[string]interface{}, error) {
+	header, err := s.b.HeaderByHash(ctx, blockHash)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	return s.blockReceipts(ctx, blockHash, header.Number.Uint64())
+}
 
+// blockReceipts assembles the RPC representation of all the receipts of a block.
+func (s *PublicTransactionPoolAPI) blockReceipts(ctx context.Context, blockHash common.Hash, blockNumber uint64) ([]map[string]interface{}, error) {
 	receipts, err := s.b.GetReceipts(ctx, blockHash)
 	if err != nil {
 		return nil, err
 	}
 	block, err := s.b.BlockByHash(ctx, blockHash)
-	if err != nil {
+	if block == nil || err != nil {
 		return nil, err
 	}
 	txs := block.Transactions()
@@ -1705,6 +2039,37 @@ func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encod
 	return SubmitTransaction(ctx, s.b, tx)
 }
 
+// SendPrivateRawTransaction will add the signed transaction to the transaction
+// pool, but keep it out of the public mempool: it is never propagated to peers
+// and only gets included by this node's miner.
+func (s *PublicTransactionPoolAPI) SendPrivateRawTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	if err := s.b.SendPrivateTx(ctx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Submitted private transaction", "fullhash", tx.Hash().Hex(), "recipient", tx.To())
+	return tx.Hash(), nil
+}
+
+// SendAltRawTransaction will add the signed transaction to the separate alt lane
+// of the transaction pool. Alt transactions are never propagated to peers and
+// replace any previous alt transaction of the same sender and nonce regardless
+// of their gas price, which suits bundlers resubmitting their bundles.
+func (s *PublicTransactionPoolAPI) SendAltRawTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	if err := s.b.SendAltTx(ctx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Submitted alt transaction", "fullhash", tx.Hash().Hex(), "recipient", tx.To())
+	return tx.Hash(), nil
+}
+
 // Sign calculates an ECDSA signature for:
 // keccack256("\x19Ethereum Signed Message:\n" + len(message) + message).
 //
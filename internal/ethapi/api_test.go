@@ -0,0 +1,307 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	testKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddress = crypto.PubkeyToAddress(testKey.PublicKey)
+)
+
+// testBackend is a Backend serving the blocks and state of a generated chain.
+// Methods the tests do not need panic through the embedded nil interface.
+type testBackend struct {
+	Backend
+
+	chain   *core.BlockChain
+	pruned  uint64      // State of the blocks below this number is reported missing
+	archive *rpc.Client // Archive node to forward queries on pruned state to
+}
+
+// newTestBackend creates a backend on a chain of n blocks, with the test
+// account funded in the genesis.
+func newTestBackend(t *testing.T, n int, generator func(i int, b *core.BlockGen)) *testBackend {
+	var (
+		db    = rawdb.NewMemoryDatabase()
+		gspec = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  core.GenesisAlloc{testAddress: {Balance: big.NewInt(params.Ether)}},
+		}
+		genesis = gspec.MustCommit(db)
+	)
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, n, generator)
+
+	chain, err := core.NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	return &testBackend{chain: chain}
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if number < 0 {
+		return b.chain.CurrentHeader(), nil
+	}
+	return b.chain.GetHeaderByNumber(uint64(number)), nil
+}
+
+func (b *testBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	header, _ := b.HeaderByNumber(ctx, number)
+	if header == nil {
+		return nil, nil, errors.New("header not found")
+	}
+	if header.Number.Uint64() < b.pruned {
+		return nil, nil, &trie.MissingNodeError{NodeHash: header.Root}
+	}
+	statedb, err := b.chain.StateAt(header.Root)
+	return statedb, header, err
+}
+
+func (b *testBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	if number, ok := blockNrOrHash.Number(); ok {
+		return b.StateAndHeaderByNumber(ctx, number)
+	}
+	hash, _ := blockNrOrHash.Hash()
+	header := b.chain.GetHeaderByHash(hash)
+	if header == nil {
+		return nil, nil, errors.New("header not found")
+	}
+	return b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(header.Number.Int64()))
+}
+
+func (b *testBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return b.chain.GetHeaderByHash(hash), nil
+}
+
+func (b *testBackend) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return b.chain.GetBlockByHash(hash), nil
+}
+
+func (b *testBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	return b.chain.GetReceiptsByHash(hash), nil
+}
+
+func (b *testBackend) RPCGasCap() *big.Int {
+	return nil
+}
+
+func (b *testBackend) ArchiveClient() *rpc.Client {
+	return b.archive
+}
+
+func (b *testBackend) ChainConfig() *params.ChainConfig {
+	return b.chain.Config()
+}
+
+func (b *testBackend) GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header) (*vm.EVM, func() error, error) {
+	context := core.NewEVMContext(msg, header, b.chain, nil)
+	return vm.NewEVM(context, state, b.chain.Config(), vm.Config{}), func() error { return nil }, nil
+}
+
+// transferEmitter is the code of a contract logging a token transfer of 5 from
+// its caller to the address 0x01 whenever called.
+var transferEmitter = common.FromHex("0x602f600c600039602f6000f36005600052600133" + "7f" + transferEventTopic.Hex()[2:] + "60206000a300")
+
+// Tests that the balance and token transfer changes of an account are tracked
+// over the recent blocks with available state.
+func TestGetBalanceChanges(t *testing.T) {
+	var (
+		recipient = common.BigToAddress(big.NewInt(1))
+		signer    = types.HomesteadSigner{}
+		emitter   = crypto.CreateAddress(testAddress, 1)
+	)
+	backend := newTestBackend(t, int(maxBalanceChangesRange)+2, func(i int, b *core.BlockGen) {
+		var txs []*types.Transaction
+		switch i {
+		case 0:
+			txs = append(txs, types.NewTransaction(b.TxNonce(testAddress), recipient, big.NewInt(1000), 50000, big.NewInt(1), nil))
+			txs = append(txs, types.NewContractCreation(b.TxNonce(testAddress)+1, new(big.Int), 100000, big.NewInt(1), transferEmitter))
+		case 1:
+			txs = append(txs, types.NewTransaction(b.TxNonce(testAddress), emitter, new(big.Int), 100000, big.NewInt(1), nil))
+		case 2:
+			txs = append(txs, types.NewTransaction(b.TxNonce(testAddress), recipient, big.NewInt(500), 50000, big.NewInt(1), nil))
+		}
+		for _, tx := range txs {
+			signed, err := types.SignTx(tx, signer, testKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b.AddTx(signed)
+		}
+	})
+	defer backend.chain.Stop()
+
+	api := NewPublicBlockChainAPI(backend)
+	tokens := true
+
+	changes, err := api.GetBalanceChanges(context.Background(), recipient, 1, 3, &tokens)
+	if err != nil {
+		t.Fatalf("failed to retrieve balance changes: %v", err)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("balance change count mismatch: have %d, want 3", len(changes))
+	}
+	if changes[0].BlockNumber != 1 || changes[0].Token != nil || changes[0].Delta.ToInt().Int64() != 1000 {
+		t.Errorf("first change mismatch: block %d, delta %v", changes[0].BlockNumber, changes[0].Delta)
+	}
+	if changes[1].BlockNumber != 2 || changes[1].Token == nil || *changes[1].Token != emitter || changes[1].Delta.ToInt().Int64() != 5 {
+		t.Errorf("token change mismatch: block %d, token %v, delta %v", changes[1].BlockNumber, changes[1].Token, changes[1].Delta)
+	}
+	if changes[2].BlockNumber != 3 || changes[2].Balance.ToInt().Int64() != 1500 {
+		t.Errorf("last change mismatch: block %d, balance %v", changes[2].BlockNumber, changes[2].Balance)
+	}
+	// Ranges beyond the retained state must be rejected
+	if _, err := api.GetBalanceChanges(context.Background(), recipient, 1, rpc.BlockNumber(maxBalanceChangesRange+1), nil); err == nil {
+		t.Errorf("oversized range accepted")
+	}
+	backend.pruned = 2
+	if _, err := api.GetBalanceChanges(context.Background(), recipient, 2, 3, nil); err == nil || !strings.Contains(err.Error(), "not available") {
+		t.Errorf("pruned state error mismatch: have %v", err)
+	}
+	if changes, err := api.GetBalanceChanges(context.Background(), recipient, 3, 4, nil); err != nil || len(changes) != 1 {
+		t.Errorf("retained state changes mismatch: have %d changes, err %v", len(changes), err)
+	}
+}
+
+// Tests that the calls of a bundle observe the state changes of the preceding
+// ones and the overrides, and that a reverting call does not abort the bundle.
+func TestCallMany(t *testing.T) {
+	backend := newTestBackend(t, 1, func(i int, b *core.BlockGen) {})
+	defer backend.chain.Stop()
+
+	var (
+		counter  = common.Address{0xc0}
+		reverter = common.Address{0xee}
+
+		// Increments slot 0 and returns its new value
+		counterCode = hexutil.Bytes{0x60, 0x00, 0x54, 0x60, 0x01, 0x01, 0x80, 0x60, 0x00, 0x55, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3}
+		// Reverts unconditionally
+		reverterCode = hexutil.Bytes{0x60, 0x00, 0x60, 0x00, 0xfd}
+
+		counterState = map[common.Hash]common.Hash{{}: common.BigToHash(big.NewInt(5))}
+		overrides    = map[common.Address]account{
+			counter:  {Code: &counterCode, StateDiff: &counterState},
+			reverter: {Code: &reverterCode},
+		}
+	)
+	api := NewPublicBlockChainAPI(backend)
+	calls := []CallArgs{{To: &counter}, {To: &reverter}, {To: &counter}}
+
+	results, err := api.CallMany(context.Background(), calls, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), &overrides)
+	if err != nil {
+		t.Fatalf("failed to execute bundle: %v", err)
+	}
+	if len(results) != len(calls) {
+		t.Fatalf("result count mismatch: have %d, want %d", len(results), len(calls))
+	}
+	for i, want := range map[int]int64{0: 6, 2: 7} {
+		if results[i].Error != "" {
+			t.Errorf("call %d failed: %v", i, results[i].Error)
+		}
+		if have := new(big.Int).SetBytes(results[i].ReturnValue); have.Int64() != want {
+			t.Errorf("call %d result mismatch: have %v, want %d", i, have, want)
+		}
+	}
+	if results[1].Error != "execution reverted" {
+		t.Errorf("reverting call error mismatch: have %q, want %q", results[1].Error, "execution reverted")
+	}
+	// The bundle must not modify the chain state
+	statedb, _ := backend.chain.State()
+	if code := statedb.GetCode(counter); len(code) != 0 {
+		t.Errorf("override persisted: code %x", code)
+	}
+}
+
+// Tests that all the receipts of a block are returned by hash and by number,
+// and that unknown blocks yield no receipts.
+func TestGetTransactionReceipts(t *testing.T) {
+	var (
+		recipient = common.Address{0x01}
+		signer    = types.HomesteadSigner{}
+	)
+	backend := newTestBackend(t, 2, func(i int, b *core.BlockGen) {
+		for j := 0; j <= i; j++ {
+			tx, err := types.SignTx(types.NewTransaction(b.TxNonce(testAddress), recipient, big.NewInt(1), 21000, big.NewInt(1), nil), signer, testKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b.AddTx(tx)
+		}
+	})
+	defer backend.chain.Stop()
+
+	var (
+		ctx   = context.Background()
+		api   = NewPublicTransactionPoolAPI(backend, new(AddrLocker))
+		block = backend.chain.GetBlockByNumber(2)
+	)
+	byHash, err := api.GetTransactionReceipts(ctx, block.Hash())
+	if err != nil {
+		t.Fatalf("failed to retrieve receipts by hash: %v", err)
+	}
+	byNumber, err := api.GetTransactionReceiptsByBlockNumber(ctx, 2)
+	if err != nil {
+		t.Fatalf("failed to retrieve receipts by number: %v", err)
+	}
+	if len(byHash) != 2 || len(byNumber) != 2 {
+		t.Fatalf("receipt count mismatch: have %d by hash and %d by number, want 2", len(byHash), len(byNumber))
+	}
+	for i, tx := range block.Transactions() {
+		for _, receipts := range [][]map[string]interface{}{byHash, byNumber} {
+			fields := receipts[i]
+			if fields["transactionHash"] != tx.Hash() || fields["blockHash"] != block.Hash() || fields["from"] != testAddress {
+				t.Errorf("receipt %d mismatch: %v", i, fields)
+			}
+			if fields["status"] != hexutil.Uint(types.ReceiptStatusSuccessful) {
+				t.Errorf("receipt %d status mismatch: have %v", i, fields["status"])
+			}
+		}
+	}
+	// Unknown blocks must not fail
+	if receipts, err := api.GetTransactionReceipts(ctx, common.Hash{0xff}); receipts != nil || err != nil {
+		t.Errorf("unknown block hash: have %v, err %v", receipts, err)
+	}
+	if receipts, err := api.GetTransactionReceiptsByBlockNumber(ctx, 100); receipts != nil || err != nil {
+		t.Errorf("unknown block number: have %v, err %v", receipts, err)
+	}
+	if receipts, err := api.blockReceipts(ctx, common.Hash{0xff}, 100); receipts != nil || err != nil {
+		t.Errorf("unknown block: have %v, err %v", receipts, err)
+	}
+}
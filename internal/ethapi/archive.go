@@ -0,0 +1,123 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	archiveForwardMeter = metrics.NewRegisteredMeter("rpc/archive/forward", nil)
+	archiveFailureMeter = metrics.NewRegisteredMeter("rpc/archive/failure", nil)
+)
+
+// forwardToArchive retries a state query on the archive node configured as
+// fallback, if the local node failed it with err because the state of the
+// requested block was pruned. It reports whether the query was forwarded, in
+// which case the returned error is the one of the archive node.
+func forwardToArchive(ctx context.Context, b Backend, err error, result interface{}, method string, args ...interface{}) (bool, error) {
+	client := b.ArchiveClient()
+	if client == nil {
+		return false, err
+	}
+	if _, ok := err.(*trie.MissingNodeError); !ok {
+		return false, err
+	}
+	log.Debug("Forwarding pruned state query to archive node", "method", method, "err", err)
+	archiveForwardMeter.Mark(1)
+
+	if err := client.CallContext(ctx, result, method, args...); err != nil {
+		archiveFailureMeter.Mark(1)
+		return true, err
+	}
+	return true, nil
+}
+
+// publicBlockChainRPCAPI is the PublicBlockChainAPI as served over RPC, which
+// forwards state queries on pruned blocks to the archive node. The consensus
+// engine uses the plain PublicBlockChainAPI, so it never acts on the answers of
+// a remote node.
+type publicBlockChainRPCAPI struct {
+	*PublicBlockChainAPI
+}
+
+// GetBalance returns the amount of wei for the given address in the state of
+// the given block number, see PublicBlockChainAPI.GetBalance.
+func (s *publicBlockChainRPCAPI) GetBalance(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	balance, err := s.PublicBlockChainAPI.GetBalance(ctx, address, blockNrOrHash)
+	if err != nil {
+		if forwarded, err := forwardToArchive(ctx, s.b, err, &balance, "eth_getBalance", address, blockNrOrHash); forwarded {
+			return balance, err
+		}
+	}
+	return balance, err
+}
+
+// GetProof returns the Merkle-proof for a given account and optionally some
+// storage keys, see PublicBlockChainAPI.GetProof.
+func (s *publicBlockChainRPCAPI) GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNrOrHash rpc.BlockNumberOrHash) (*AccountResult, error) {
+	result, err := s.PublicBlockChainAPI.GetProof(ctx, address, storageKeys, blockNrOrHash)
+	if err != nil {
+		if forwarded, err := forwardToArchive(ctx, s.b, err, &result, "eth_getProof", address, storageKeys, blockNrOrHash); forwarded {
+			return result, err
+		}
+	}
+	return result, err
+}
+
+// GetCode returns the code stored at the given address in the state for the
+// given block number, see PublicBlockChainAPI.GetCode.
+func (s *publicBlockChainRPCAPI) GetCode(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	code, err := s.PublicBlockChainAPI.GetCode(ctx, address, blockNrOrHash)
+	if err != nil {
+		if forwarded, err := forwardToArchive(ctx, s.b, err, &code, "eth_getCode", address, blockNrOrHash); forwarded {
+			return code, err
+		}
+	}
+	return code, err
+}
+
+// GetStorageAt returns the storage from the state at the given address, key and
+// block number, see PublicBlockChainAPI.GetStorageAt.
+func (s *publicBlockChainRPCAPI) GetStorageAt(ctx context.Context, address common.Address, key string, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	res, err := s.PublicBlockChainAPI.GetStorageAt(ctx, address, key, blockNrOrHash)
+	if err != nil {
+		if forwarded, err := forwardToArchive(ctx, s.b, err, &res, "eth_getStorageAt", address, key, blockNrOrHash); forwarded {
+			return res, err
+		}
+	}
+	return res, err
+}
+
+// Call executes the given transaction on the state for the given block number,
+// see PublicBlockChainAPI.Call.
+func (s *publicBlockChainRPCAPI) Call(ctx context.Context, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *map[common.Address]account) (hexutil.Bytes, error) {
+	res, err := s.PublicBlockChainAPI.Call(ctx, args, blockNrOrHash, overrides)
+	if err != nil {
+		if forwarded, err := forwardToArchive(ctx, s.b, err, &res, "eth_call", args, blockNrOrHash, overrides); forwarded {
+			return res, err
+		}
+	}
+	return res, err
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// testArchiveAPI is the eth namespace of a fake archive node, answering every
+// query with fixed results.
+type testArchiveAPI struct {
+	calls int
+}
+
+func (api *testArchiveAPI) GetBalance(address common.Address, blockNrOrHash rpc.BlockNumberOrHash) *hexutil.Big {
+	api.calls++
+	return (*hexutil.Big)(big.NewInt(42))
+}
+
+func (api *testArchiveAPI) Call(args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *map[common.Address]account) hexutil.Bytes {
+	api.calls++
+	return hexutil.Bytes{0x2a}
+}
+
+// newTestArchive starts an in-process rpc server serving the fake archive API.
+// The returned function shuts it down.
+func newTestArchive(t *testing.T) (*testArchiveAPI, *rpc.Client, func()) {
+	archive := new(testArchiveAPI)
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", archive); err != nil {
+		t.Fatalf("failed to register archive API: %v", err)
+	}
+	client := rpc.DialInProc(server)
+
+	return archive, client, func() {
+		client.Close()
+		server.Stop()
+	}
+}
+
+// Tests that errors are only forwarded to the archive node if one is configured
+// and the local state is missing.
+func TestForwardToArchive(t *testing.T) {
+	archive, client, stop := newTestArchive(t)
+	defer stop()
+
+	var (
+		ctx     = context.Background()
+		missing = &trie.MissingNodeError{}
+		other   = errors.New("other failure")
+		block   = rpc.BlockNumberOrHashWithNumber(1)
+	)
+	tests := []struct {
+		client    *rpc.Client
+		err       error
+		forwarded bool
+	}{
+		{nil, missing, false},
+		{client, other, false},
+		{client, missing, true},
+	}
+	for i, tt := range tests {
+		var (
+			backend = &testBackend{archive: tt.client}
+			balance *hexutil.Big
+			calls   = archive.calls
+		)
+		forwarded, err := forwardToArchive(ctx, backend, tt.err, &balance, "eth_getBalance", common.Address{}, block)
+		if forwarded != tt.forwarded {
+			t.Errorf("test %d: forwarded mismatch: have %v, want %v", i, forwarded, tt.forwarded)
+			continue
+		}
+		if !forwarded {
+			if err != tt.err {
+				t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+			}
+			if archive.calls != calls {
+				t.Errorf("test %d: archive node queried", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: forwarding failed: %v", i, err)
+		} else if balance == nil || balance.ToInt().Int64() != 42 {
+			t.Errorf("test %d: balance mismatch: have %v, want 42", i, balance)
+		}
+	}
+}
+
+// Tests that the RPC facing API forwards queries on pruned state to the archive
+// node, while the API handed to the consensus engine does not.
+func TestArchiveFallback(t *testing.T) {
+	archive, client, stop := newTestArchive(t)
+	defer stop()
+
+	backend := newTestBackend(t, 2, func(i int, b *core.BlockGen) {})
+	defer backend.chain.Stop()
+	backend.pruned, backend.archive = 2, client
+
+	var (
+		ctx    = context.Background()
+		api    = NewPublicBlockChainAPI(backend)
+		rpcAPI = &publicBlockChainRPCAPI{api}
+		pruned = rpc.BlockNumberOrHashWithNumber(1)
+		recent = rpc.BlockNumberOrHashWithNumber(2)
+	)
+	// Queries on pruned state must be answered by the archive node over RPC
+	balance, err := rpcAPI.GetBalance(ctx, testAddress, pruned)
+	if err != nil || balance.ToInt().Int64() != 42 {
+		t.Errorf("forwarded balance mismatch: have %v, err %v, want 42", balance, err)
+	}
+	res, err := rpcAPI.Call(ctx, CallArgs{To: &testAddress}, pruned, nil)
+	if err != nil || !bytes.Equal(res, []byte{0x2a}) {
+		t.Errorf("forwarded call mismatch: have %x, err %v, want 2a", res, err)
+	}
+	if archive.calls != 2 {
+		t.Errorf("archive query count mismatch: have %d, want 2", archive.calls)
+	}
+	// The consensus engine must see the local failure
+	if _, err := api.Call(ctx, CallArgs{To: &testAddress}, pruned, nil); err == nil {
+		t.Errorf("engine call on pruned state succeeded")
+	} else if _, ok := err.(*trie.MissingNodeError); !ok {
+		t.Errorf("engine call error mismatch: have %v, want missing trie node", err)
+	}
+	// Queries on retained state must be answered locally
+	if balance, err := rpcAPI.GetBalance(ctx, testAddress, recent); err != nil || balance.ToInt().Cmp(big.NewInt(0)) <= 0 {
+		t.Errorf("local balance mismatch: have %v, err %v", balance, err)
+	}
+	if archive.calls != 2 {
+		t.Errorf("archive queried for retained state: %d queries", archive.calls)
+	}
+}
@@ -970,6 +970,12 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 				return
 			}
 		}
+		if altTxs := w.eth.TxPool().AltPending(); len(altTxs) > 0 {
+			txs := types.NewTransactionsByPriceAndNonce(w.current.signer, altTxs)
+			if w.commitTransactions(txs, w.coinbase, interrupt) {
+				return
+			}
+		}
 		if len(remoteTxs) > 0 {
 			txs := types.NewTransactionsByPriceAndNonce(w.current.signer, remoteTxs)
 			if w.commitTransactions(txs, w.coinbase, interrupt) {
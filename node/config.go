@@ -142,6 +142,11 @@ type Config struct {
 	// interface.
 	HTTPTimeouts rpc.HTTPTimeouts
 
+	// RPCRateLimits caps the number of calls per second served for each listed method
+	// on the HTTP and websocket RPC interfaces. The limits are global, a single budget
+	// is shared by all the clients of both interfaces.
+	RPCRateLimits map[string]float64 `toml:",omitempty"`
+
 	// WSHost is the host interface on which to start the websocket RPC server. If
 	// this field is empty, no websocket API endpoint will be started.
 	WSHost string `toml:",omitempty"`
@@ -168,6 +173,24 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// WSCompression enables per-message compression on websocket connections for
+	// clients which support it.
+	WSCompression bool `toml:",omitempty"`
+
+	// WSMaxMessageSize is the maximum size in bytes of a message received over a
+	// websocket connection. It can only lower the default request size limit, which
+	// zero stands for.
+	WSMaxMessageSize int64 `toml:",omitempty"`
+
+	// WSMaxNotificationSize is the maximum size in bytes of a subscription
+	// notification sent over a websocket connection, larger ones are dropped.
+	// Zero means unlimited.
+	WSMaxNotificationSize int64 `toml:",omitempty"`
+
+	// WSMaxSubscriptions is the maximum number of subscriptions a single websocket
+	// connection may have active. Zero means unlimited.
+	WSMaxSubscriptions int `toml:",omitempty"`
+
 	// GraphQLHost is the host interface on which to start the GraphQL server. If this
 	// field is empty, no GraphQL API endpoint will be started.
 	GraphQLHost string `toml:",omitempty"`
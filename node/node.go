@@ -53,8 +53,9 @@ type Node struct {
 	serviceFuncs []ServiceConstructor     // Service constructors (in dependency order)
 	services     map[reflect.Type]Service // Currently running services
 
-	rpcAPIs       []rpc.API   // List of APIs currently provided by the node
-	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
+	rpcAPIs       []rpc.API        // List of APIs currently provided by the node
+	rpcLimiter    *rpc.RateLimiter // Rate limits shared by the HTTP and websocket endpoints
+	inprocHandler *rpc.Server      // In-process RPC request handler to process the API requests
 
 	ipcEndpoint string       // IPC endpoint to listen at (empty = IPC disabled)
 	ipcListener net.Listener // IPC RPC listener socket to serve API requests
@@ -117,6 +118,10 @@ func New(conf *Config) (*Node, error) {
 	if conf.Logger == nil {
 		conf.Logger = log.New()
 	}
+	limiter := rpc.NewRateLimiter()
+	for method, limit := range conf.RPCRateLimits {
+		limiter.SetLimit(method, limit)
+	}
 	// Note: any interaction with Config that would create/touch files
 	// in the data directory or instance directory is delayed until Start.
 	return &Node{
@@ -127,6 +132,7 @@ func New(conf *Config) (*Node, error) {
 		ipcEndpoint:       conf.IPCEndpoint(),
 		httpEndpoint:      conf.HTTPEndpoint(),
 		wsEndpoint:        conf.WSEndpoint(),
+		rpcLimiter:        limiter,
 		eventmux:          new(event.TypeMux),
 		log:               conf.Logger,
 	}, nil
@@ -380,6 +386,7 @@ func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors
 	}
 	// register apis and create handler stack
 	srv := rpc.NewServer()
+	srv.SetRateLimiter(n.rpcLimiter)
 	err := RegisterApisFromWhitelist(apis, modules, srv, false)
 	if err != nil {
 		return err
@@ -387,7 +394,8 @@ func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors
 	handler := NewHTTPHandlerStack(srv, cors, vhosts)
 	// wrap handler in websocket handler only if websocket port is the same as http rpc
 	if n.httpEndpoint == n.wsEndpoint {
-		handler = NewWebsocketUpgradeHandler(handler, srv.WebsocketHandler(wsOrigins))
+		srv.SetMaxSubscriptions(n.config.WSMaxSubscriptions)
+		handler = NewWebsocketUpgradeHandler(handler, srv.WebsocketHandlerWithConfig(wsOrigins, n.wsConfig()))
 	}
 	listener, err := StartHTTPEndpoint(endpoint, timeouts, handler)
 	if err != nil {
@@ -429,7 +437,9 @@ func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrig
 	}
 
 	srv := rpc.NewServer()
-	handler := srv.WebsocketHandler(wsOrigins)
+	srv.SetRateLimiter(n.rpcLimiter)
+	srv.SetMaxSubscriptions(n.config.WSMaxSubscriptions)
+	handler := srv.WebsocketHandlerWithConfig(wsOrigins, n.wsConfig())
 	err := RegisterApisFromWhitelist(apis, modules, srv, exposeAll)
 	if err != nil {
 		return err
@@ -447,6 +457,15 @@ func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrig
 	return nil
 }
 
+// wsConfig returns the per-connection settings of the websocket RPC endpoint.
+func (n *Node) wsConfig() rpc.WebsocketConfig {
+	return rpc.WebsocketConfig{
+		Compression:         n.config.WSCompression,
+		MaxMessageSize:      n.config.WSMaxMessageSize,
+		MaxNotificationSize: n.config.WSMaxNotificationSize,
+	}
+}
+
 // stopWS terminates the websocket RPC endpoint.
 func (n *Node) stopWS() {
 	if n.wsListener != nil {
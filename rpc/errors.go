@@ -57,6 +57,23 @@ func (e *invalidMessageError) ErrorCode() int { return -32700 }
 
 func (e *invalidMessageError) Error() string { return e.message }
 
+// too many requests of a rate limited method
+type rateLimitedError struct{ method string }
+
+func (e *rateLimitedError) ErrorCode() int { return -32005 }
+
+func (e *rateLimitedError) Error() string {
+	return fmt.Sprintf("rate limit of method %s exceeded", e.method)
+}
+
+type tooManySubscriptionsError struct{ limit int }
+
+func (e *tooManySubscriptionsError) ErrorCode() int { return -32005 }
+
+func (e *tooManySubscriptionsError) Error() string {
+	return fmt.Sprintf("too many subscriptions, the limit is %d per connection", e.limit)
+}
+
 // unable to decode supplied params, or an invalid number of parameters
 type invalidParamsError struct{ message string }
 
@@ -324,6 +324,10 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 	if callb == nil {
 		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
 	}
+	if !h.reg.allow(msg.Method) {
+		newRPCRateLimitedMeter(msg.Method).Mark(1)
+		return msg.errorResponse(&rateLimitedError{method: msg.Method})
+	}
 	args, err := parsePositionalArguments(msg.Params, callb.argTypes)
 	if err != nil {
 		return msg.errorResponse(&invalidParamsError{err.Error()})
@@ -352,6 +356,15 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 	if !h.allowSubscribe {
 		return msg.errorResponse(ErrNotificationsUnsupported)
 	}
+	if limit := h.reg.subscriptionLimit(); limit > 0 {
+		h.subLock.Lock()
+		active := len(h.serverSubs) + len(cp.notifiers)
+		h.subLock.Unlock()
+
+		if active >= limit {
+			return msg.errorResponse(&tooManySubscriptionsError{limit})
+		}
+	}
 
 	// Subscription method name is first argument.
 	name, err := parseSubscriptionName(msg.Params)
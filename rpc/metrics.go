@@ -38,6 +38,11 @@ func newRPCServingTimer(method string, valid bool) metrics.Timer {
 	return metrics.GetOrRegisterTimer(m, nil)
 }
 
+func newRPCRateLimitedMeter(method string) metrics.Meter {
+	m := fmt.Sprintf("rpc/ratelimited/%s", method)
+	return metrics.GetOrRegisterMeter(m, nil)
+}
+
 func newRPCRequestGauge(method string) metrics.Gauge {
 	m := fmt.Sprintf("rpc/count/%s", method)
 	return metrics.GetOrRegisterGauge(m, nil)
@@ -66,6 +66,25 @@ func (s *Server) RegisterName(name string, receiver interface{}) error {
 	return s.services.registerName(name, receiver)
 }
 
+// SetRateLimit caps the number of calls per second the server serves for the
+// given method across all of its connections. A non-positive limit removes
+// the cap.
+func (s *Server) SetRateLimit(method string, limit float64) {
+	s.services.setRateLimit(method, limit)
+}
+
+// SetRateLimiter replaces the rate limits of the server with the given limiter,
+// which may be shared with other servers to enforce common limits across them.
+func (s *Server) SetRateLimiter(limiter *RateLimiter) {
+	s.services.setRateLimiter(limiter)
+}
+
+// SetMaxSubscriptions caps the number of subscriptions each connection to the
+// server may have active. A non-positive limit removes the cap.
+func (s *Server) SetMaxSubscriptions(limit int) {
+	s.services.setMaxSubscriptions(limit)
+}
+
 // ServeCodec reads incoming requests from codec, calls the appropriate callback and writes
 // the response back using the given codec. It will block until the codec is closed or the
 // server is stopped. In either case the codec is closed.
@@ -150,3 +150,53 @@ func TestServerShortLivedConn(t *testing.T) {
 		}
 	}
 }
+
+func TestServerRateLimit(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	server.SetRateLimit("test_noArgsRets", 1)
+
+	client := DialInProc(server)
+	defer client.Close()
+
+	if err := client.Call(nil, "test_noArgsRets"); err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+	err := client.Call(nil, "test_noArgsRets")
+	if rerr, ok := err.(Error); !ok || rerr.ErrorCode() != -32005 {
+		t.Fatalf("second call not rate limited: %v", err)
+	}
+	// Other methods must not be affected by the limit
+	if err := client.Call(nil, "test_rets"); err != nil {
+		t.Fatalf("unlimited call failed: %v", err)
+	}
+	// Removing the limit lets the calls through again
+	server.SetRateLimit("test_noArgsRets", 0)
+	if err := client.Call(nil, "test_noArgsRets"); err != nil {
+		t.Fatalf("call after removing limit failed: %v", err)
+	}
+}
+
+func TestServerSharedRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter()
+	limiter.SetLimit("test_noArgsRets", 1)
+
+	var clients []*Client
+	for i := 0; i < 2; i++ {
+		server := newTestServer()
+		defer server.Stop()
+		server.SetRateLimiter(limiter)
+
+		client := DialInProc(server)
+		defer client.Close()
+		clients = append(clients, client)
+	}
+	if err := clients[0].Call(nil, "test_noArgsRets"); err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+	// The limit is shared, so the other server must reject the call too
+	err := clients[1].Call(nil, "test_noArgsRets")
+	if rerr, ok := err.(Error); !ok || rerr.ErrorCode() != -32005 {
+		t.Fatalf("call on second server not rate limited: %v", err)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"unicode"
 
 	"github.com/ethereum/go-ethereum/log"
+	"golang.org/x/time/rate"
 )
 
 var (
@@ -39,6 +40,9 @@ var (
 type serviceRegistry struct {
 	mu       sync.Mutex
 	services map[string]service
+	limiter  *RateLimiter // Calls per second caps, possibly shared with other servers
+
+	maxSubscriptions int // Maximum number of active subscriptions per connection, zero for no limit
 }
 
 // service represents a registered object.
@@ -103,6 +107,95 @@ func (r *serviceRegistry) callback(method string) *callback {
 	return r.services[elem[0]].callbacks[elem[1]]
 }
 
+// setRateLimit caps the calls per second of the given method.
+func (r *serviceRegistry) setRateLimit(method string, limit float64) {
+	r.mu.Lock()
+	if r.limiter == nil {
+		r.limiter = NewRateLimiter()
+	}
+	limiter := r.limiter
+	r.mu.Unlock()
+
+	limiter.SetLimit(method, limit)
+}
+
+// setRateLimiter replaces the rate limits of the registry.
+func (r *serviceRegistry) setRateLimiter(limiter *RateLimiter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.limiter = limiter
+}
+
+// allow reports whether a call to the given method is within its rate limit.
+func (r *serviceRegistry) allow(method string) bool {
+	r.mu.Lock()
+	limiter := r.limiter
+	r.mu.Unlock()
+
+	return limiter == nil || limiter.Allow(method)
+}
+
+// RateLimiter caps the number of calls per second served for RPC methods. The
+// limits are global, shared by all the clients of the servers using it.
+type RateLimiter struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// NewRateLimiter creates a rate limiter without any limits.
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{limiters: make(map[string]*rate.Limiter)}
+}
+
+// SetLimit caps the calls per second of the given method. A non-positive limit
+// removes the cap.
+func (l *RateLimiter) SetLimit(method string, limit float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limit <= 0 {
+		delete(l.limiters, method)
+		return
+	}
+	// Allow bursts of up to one second worth of calls
+	burst := int(limit)
+	if burst < 1 {
+		burst = 1
+	}
+	l.limiters[method] = rate.NewLimiter(rate.Limit(limit), burst)
+}
+
+// Allow reports whether a call to the given method is within its rate limit.
+func (l *RateLimiter) Allow(method string) bool {
+	l.mu.Lock()
+	limiter := l.limiters[method]
+	l.mu.Unlock()
+
+	return limiter == nil || limiter.Allow()
+}
+
+// setMaxSubscriptions caps the number of subscriptions a single connection may
+// have active. A non-positive limit removes the cap.
+func (r *serviceRegistry) setMaxSubscriptions(limit int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if limit < 0 {
+		limit = 0
+	}
+	r.maxSubscriptions = limit
+}
+
+// subscriptionLimit returns the number of subscriptions a single connection may
+// have active, zero meaning unlimited.
+func (r *serviceRegistry) subscriptionLimit() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.maxSubscriptions
+}
+
 // subscription returns a subscription callback in the given service.
 func (r *serviceRegistry) subscription(service, name string) *callback {
 	r.mu.Lock()
@@ -19,6 +19,7 @@ package rpc
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -38,16 +39,30 @@ const (
 
 var wsBufferPool = new(sync.Pool)
 
+// WebsocketConfig contains the per-connection settings of a WebSocket handler.
+type WebsocketConfig struct {
+	Compression         bool  // Whether to negotiate per-message compression with clients
+	MaxMessageSize      int64 // Maximum size of incoming messages, capped at and zero for the default limit
+	MaxNotificationSize int64 // Maximum size of outgoing subscription notifications, zero for no limit
+}
+
 // WebsocketHandler returns a handler that serves JSON-RPC to WebSocket connections.
 //
 // allowedOrigins should be a comma-separated list of allowed origin URLs.
 // To allow connections with any origin, pass "*".
 func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
+	return s.WebsocketHandlerWithConfig(allowedOrigins, WebsocketConfig{})
+}
+
+// WebsocketHandlerWithConfig returns a handler that serves JSON-RPC to WebSocket
+// connections, applying the given per-connection settings.
+func (s *Server) WebsocketHandlerWithConfig(allowedOrigins []string, config WebsocketConfig) http.Handler {
 	var upgrader = websocket.Upgrader{
-		ReadBufferSize:  wsReadBuffer,
-		WriteBufferSize: wsWriteBuffer,
-		WriteBufferPool: wsBufferPool,
-		CheckOrigin:     wsHandshakeValidator(allowedOrigins),
+		ReadBufferSize:    wsReadBuffer,
+		WriteBufferSize:   wsWriteBuffer,
+		WriteBufferPool:   wsBufferPool,
+		CheckOrigin:       wsHandshakeValidator(allowedOrigins),
+		EnableCompression: config.Compression,
 	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		conn, err := upgrader.Upgrade(w, r, nil)
@@ -56,6 +71,12 @@ func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			return
 		}
 		codec := newWebsocketCodec(conn)
+		if config.MaxMessageSize > 0 && config.MaxMessageSize < maxRequestContentLength {
+			conn.SetReadLimit(config.MaxMessageSize)
+		}
+		if config.MaxNotificationSize > 0 {
+			codec = NewFuncCodec(conn, limitNotifications(conn, config.MaxNotificationSize), conn.ReadJSON)
+		}
 		s.ServeCodec(codec, 0)
 	})
 }
@@ -172,3 +193,24 @@ func newWebsocketCodec(conn *websocket.Conn) ServerCodec {
 	conn.SetReadLimit(maxRequestContentLength)
 	return NewFuncCodec(conn, conn.WriteJSON, conn.ReadJSON)
 }
+
+// limitNotifications returns an encoder writing to the websocket connection,
+// which refuses to send the subscription notifications exceeding the given size
+// so that a single subscription cannot flood the client.
+func limitNotifications(conn *websocket.Conn, limit int64) func(v interface{}) error {
+	return func(v interface{}) error {
+		msg, ok := v.(*jsonrpcMessage)
+		if !ok || !msg.isNotification() {
+			return conn.WriteJSON(v)
+		}
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		if int64(len(data)) > limit {
+			log.Debug("Dropping oversized subscription notification", "method", msg.Method, "size", len(data), "limit", limit)
+			return fmt.Errorf("notification too large (%d>%d)", len(data), limit)
+		}
+		return conn.WriteMessage(websocket.TextMessage, data)
+	}
+}
@@ -113,6 +113,91 @@ func TestWebsocketLargeCall(t *testing.T) {
 	}
 }
 
+// This test checks that the configured message size and subscription limits
+// are enforced per connection.
+func TestWebsocketConnectionLimits(t *testing.T) {
+	t.Parallel()
+
+	var (
+		srv     = newTestServer()
+		config  = WebsocketConfig{Compression: true, MaxMessageSize: 1024}
+		httpsrv = httptest.NewServer(srv.WebsocketHandlerWithConfig([]string{"*"}, config))
+		wsURL   = "ws:" + strings.TrimPrefix(httpsrv.URL, "http:")
+	)
+	defer srv.Stop()
+	defer httpsrv.Close()
+	srv.SetMaxSubscriptions(2)
+
+	client, err := DialWebsocket(context.Background(), wsURL, "")
+	if err != nil {
+		t.Fatalf("can't dial: %v", err)
+	}
+	defer client.Close()
+
+	// Subscriptions up to the limit should work, the next one should be rejected.
+	for i := 0; i < 2; i++ {
+		if _, err := client.Subscribe(context.Background(), "nftest", make(chan int), "someSubscription", 0, i); err != nil {
+			t.Fatalf("subscription %d failed: %v", i, err)
+		}
+	}
+	if _, err := client.Subscribe(context.Background(), "nftest", make(chan int), "someSubscription", 0, 2); err == nil {
+		t.Fatal("no error for subscription above the limit")
+	}
+	// Calls above the configured message size should be rejected.
+	var result echoResult
+	if err := client.Call(&result, "test_echo", strings.Repeat("x", 512), 1); err != nil {
+		t.Fatalf("valid call didn't work: %v", err)
+	}
+	if err := client.Call(&result, "test_echo", strings.Repeat("x", 2048), 1); err == nil {
+		t.Fatal("no error for too large call")
+	}
+}
+
+// This test checks that a configured message size cannot raise the default
+// request limit, and that oversized notifications are dropped without closing
+// the connection.
+func TestWebsocketConnectionSizeBounds(t *testing.T) {
+	t.Parallel()
+
+	var (
+		srv     = newTestServer()
+		config  = WebsocketConfig{MaxMessageSize: maxRequestContentLength * 4, MaxNotificationSize: 16}
+		httpsrv = httptest.NewServer(srv.WebsocketHandlerWithConfig([]string{"*"}, config))
+		wsURL   = "ws:" + strings.TrimPrefix(httpsrv.URL, "http:")
+	)
+	defer srv.Stop()
+	defer httpsrv.Close()
+
+	client, err := DialWebsocket(context.Background(), wsURL, "")
+	if err != nil {
+		t.Fatalf("can't dial: %v", err)
+	}
+	defer client.Close()
+
+	notifications := make(chan int)
+	sub, err := client.Subscribe(context.Background(), "nftest", notifications, "someSubscription", 1, 0)
+	if err != nil {
+		t.Fatalf("subscription failed: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	select {
+	case n := <-notifications:
+		t.Fatalf("oversized notification delivered: %d", n)
+	case err := <-sub.Err():
+		t.Fatalf("subscription failed: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	// Responses are not limited, but calls above the default limit are rejected.
+	var result echoResult
+	if err := client.Call(&result, "test_echo", strings.Repeat("x", 512), 1); err != nil {
+		t.Fatalf("valid call didn't work: %v", err)
+	}
+	if err := client.Call(&result, "test_echo", strings.Repeat("x", maxRequestContentLength*2), 1); err == nil {
+		t.Fatal("no error for call above the default limit")
+	}
+}
+
 // This test checks that client handles WebSocket ping frames correctly.
 func TestClientWebsocketPing(t *testing.T) {
 	t.Parallel()